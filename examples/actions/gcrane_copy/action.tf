@@ -0,0 +1,7 @@
+# Invoke with: terraform apply -invoke=action.gcrane_copy.promote
+action "gcrane_copy" "promote" {
+  config {
+    source      = "europe-docker.pkg.dev/my-project/staging/my-image:v1.2.3"
+    destination = "europe-docker.pkg.dev/my-project/production/my-image:v1.2.3"
+  }
+}
@@ -0,0 +1,15 @@
+# Invoke with: terraform apply -invoke=action.gcrane_delete.bad_image
+action "gcrane_delete" "bad_image" {
+  config {
+    reference = "europe-docker.pkg.dev/my-project/production/my-image:v1.2.3"
+  }
+}
+
+# Invoke with: terraform apply -invoke=action.gcrane_delete.decommissioned
+action "gcrane_delete" "decommissioned" {
+  config {
+    reference          = "europe-docker.pkg.dev/my-project/mirror/old-app"
+    recursive          = true
+    confirm_repository = "europe-docker.pkg.dev/my-project/mirror/old-app"
+  }
+}
@@ -0,0 +1,7 @@
+# Invoke with: terraform apply -invoke=action.gcrane_gc.cleanup
+action "gcrane_gc" "cleanup" {
+  config {
+    repository = "europe-docker.pkg.dev/my-project/staging"
+    recursive  = true
+  }
+}
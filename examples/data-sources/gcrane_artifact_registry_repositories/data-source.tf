@@ -0,0 +1,12 @@
+data "gcrane_artifact_registry_repositories" "europe" {
+  project  = "my-project"
+  location = "europe"
+}
+
+# Mirror the pause image to every Docker repository in the project.
+resource "gcrane_copy" "pause" {
+  for_each = { for r in data.gcrane_artifact_registry_repositories.europe.repositories : r.repository_id => r if r.mode == "STANDARD_REPOSITORY" }
+
+  source      = "registry.k8s.io/pause:3.10"
+  destination = "${each.value.registry}/pause:3.10"
+}
@@ -0,0 +1,11 @@
+data "gcrane_base_image" "api" {
+  reference = "europe-docker.pkg.dev/my-project/production/api:v1.2.3"
+  candidates = [
+    "gcr.io/distroless/static-debian12:latest",
+    "gcr.io/distroless/base-debian12:latest",
+  ]
+}
+
+output "api_base_image" {
+  value = data.gcrane_base_image.api.name
+}
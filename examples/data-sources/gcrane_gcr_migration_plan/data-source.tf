@@ -0,0 +1,15 @@
+data "gcrane_gcr_migration_plan" "legacy" {
+  project = "my-project"
+  hosts   = ["eu.gcr.io"]
+}
+
+# Repositories to migrate, with where they go.
+output "migration" {
+  value = [
+    for source, plan in data.gcrane_gcr_migration_plan.legacy.repositories : {
+      source      = source
+      destination = plan.destination
+      size_bytes  = plan.size_bytes
+    }
+  ]
+}
@@ -0,0 +1,20 @@
+data "gcrane_image_config" "api" {
+  reference = "europe-docker.pkg.dev/my-project/production/api:v1.2.3"
+}
+
+resource "google_cloud_run_v2_service" "api" {
+  name     = "api"
+  location = "europe-west1"
+
+  template {
+    containers {
+      image   = "europe-docker.pkg.dev/my-project/production/api@${data.gcrane_image_config.api.digest}"
+      command = data.gcrane_image_config.api.entrypoint
+      args    = data.gcrane_image_config.api.cmd
+
+      ports {
+        container_port = data.gcrane_image_config.api.ports[0]
+      }
+    }
+  }
+}
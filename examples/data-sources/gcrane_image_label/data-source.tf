@@ -0,0 +1,8 @@
+data "gcrane_image_label" "revision" {
+  reference = "europe-docker.pkg.dev/my-project/production/my-image:v1.2.3"
+  label     = "org.opencontainers.image.revision"
+}
+
+output "deployed_revision" {
+  value = data.gcrane_image_label.revision.value
+}
@@ -0,0 +1,15 @@
+data "gcrane_image_pull_secret" "mirrors" {
+  registries = ["europe-docker.pkg.dev", "registry.example.com"]
+}
+
+resource "kubernetes_secret_v1" "pull_secret" {
+  metadata {
+    name = "registries"
+  }
+
+  type = "kubernetes.io/dockerconfigjson"
+
+  data = {
+    ".dockerconfigjson" = data.gcrane_image_pull_secret.mirrors.docker_config_json
+  }
+}
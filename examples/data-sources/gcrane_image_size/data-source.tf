@@ -0,0 +1,10 @@
+data "gcrane_image_size" "edge" {
+  reference = "europe-docker.pkg.dev/my-project/production/edge-agent:v1.2.3"
+}
+
+check "edge_image_size" {
+  assert {
+    condition     = data.gcrane_image_size.edge.platforms["linux/arm64"].compressed_size_bytes < 200 * 1024 * 1024
+    error_message = "The linux/arm64 edge agent image must stay under 200 MiB."
+  }
+}
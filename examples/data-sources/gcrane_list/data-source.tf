@@ -1,3 +1,26 @@
 data "gcrane_list" "images" {
   repository = "google/pause"
 }
+
+# Credentials in the auth block of a data source are stored in the state.
+# Provider configurations are not, so list with a provider alias to keep
+# them out of it.
+variable "partner_password" {
+  type      = string
+  ephemeral = true
+}
+
+provider "gcrane" {
+  alias = "partner"
+
+  registry_auth {
+    address  = "registry.partner.example.com"
+    username = "inventory"
+    password = var.partner_password
+  }
+}
+
+data "gcrane_list" "partner_images" {
+  provider   = gcrane.partner
+  repository = "registry.partner.example.com/releases/agent"
+}
@@ -0,0 +1,15 @@
+data "gcrane_provenance" "api" {
+  reference = "europe-docker.pkg.dev/my-project/production/api:v1.2.3"
+}
+
+resource "gcrane_copy" "api" {
+  source      = "europe-docker.pkg.dev/my-project/production/api:v1.2.3"
+  destination = "europe-docker.pkg.dev/my-project/release/api:v1.2.3"
+
+  lifecycle {
+    precondition {
+      condition     = data.gcrane_provenance.api.source_repository == "https://github.com/my-org/api"
+      error_message = "Only images built from my-org/api can be released."
+    }
+  }
+}
@@ -0,0 +1,21 @@
+ephemeral "gcrane_auth_token" "token" {}
+
+# Use the token as an image pull secret, without storing it in the state.
+resource "kubernetes_secret_v1" "pull_secret" {
+  metadata {
+    name = "artifact-registry"
+  }
+
+  type = "kubernetes.io/dockerconfigjson"
+
+  data_wo_revision = 1
+  data_wo = {
+    ".dockerconfigjson" = jsonencode({
+      auths = {
+        "europe-docker.pkg.dev" = {
+          auth = base64encode("${ephemeral.gcrane_auth_token.token.username}:${ephemeral.gcrane_auth_token.token.access_token}")
+        }
+      }
+    })
+  }
+}
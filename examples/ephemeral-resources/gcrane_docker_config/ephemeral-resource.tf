@@ -0,0 +1,16 @@
+ephemeral "gcrane_docker_config" "pull" {
+  registries = ["europe-docker.pkg.dev", "ghcr.io"]
+}
+
+resource "kubernetes_secret_v1" "pull_secret" {
+  metadata {
+    name = "registries"
+  }
+
+  type = "kubernetes.io/dockerconfigjson"
+
+  data_wo_revision = 1
+  data_wo = {
+    ".dockerconfigjson" = ephemeral.gcrane_docker_config.pull.docker_config
+  }
+}
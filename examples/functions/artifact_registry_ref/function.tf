@@ -0,0 +1,4 @@
+output "image" {
+  # "europe-west4-docker.pkg.dev/my-project/my-repo/my-image:v1.2.3"
+  value = provider::gcrane::artifact_registry_ref("my-project", "europe-west4", "my-repo", "my-image", "v1.2.3")
+}
@@ -0,0 +1,4 @@
+output "migrated_image" {
+  # "europe-docker.pkg.dev/my-project/eu.gcr.io/my-image:v1"
+  value = provider::gcrane::gcr_to_ar("eu.gcr.io/my-project/my-image:v1")
+}
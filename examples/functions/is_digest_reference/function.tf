@@ -0,0 +1,8 @@
+variable "image" {
+  type = string
+
+  validation {
+    condition     = provider::gcrane::is_digest_reference(var.image)
+    error_message = "The image must be pinned to a digest (image@sha256:...)."
+  }
+}
@@ -0,0 +1,9 @@
+variable "environment" {
+  type    = string
+  default = "staging"
+}
+
+output "image" {
+  # "europe-docker.pkg.dev/my-project/staging/my-image:v1.2.3"
+  value = provider::gcrane::join_reference("europe-docker.pkg.dev", "my-project/${var.environment}/my-image", "v1.2.3", null)
+}
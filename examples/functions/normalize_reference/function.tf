@@ -0,0 +1,4 @@
+output "same_image" {
+  # true
+  value = provider::gcrane::normalize_reference("nginx") == provider::gcrane::normalize_reference("docker.io/library/nginx:latest")
+}
@@ -0,0 +1,8 @@
+locals {
+  image = provider::gcrane::parse_reference("europe-docker.pkg.dev/my-project/my-repo/my-image:v1.2.3")
+}
+
+output "image_repository" {
+  # "my-project/my-repo/my-image"
+  value = local.image.repository
+}
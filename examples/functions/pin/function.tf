@@ -0,0 +1,8 @@
+output "pinned_image" {
+  # "europe-docker.pkg.dev/my-project/my-repo/my-image:v1.2.3@sha256:..."
+  value = provider::gcrane::pin(
+    "europe-docker.pkg.dev/my-project/my-repo/my-image",
+    "v1.2.3",
+    "sha256:3b0b6ad2c4d5cf6d9a84b1b1a1b3f6e6e5c7ed2c6ad9b5bbff6d6d4f3c1a8e5d",
+  )
+}
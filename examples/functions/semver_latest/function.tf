@@ -0,0 +1,7 @@
+data "gcrane_list" "images" {
+  repository = "europe-docker.pkg.dev/my-project/my-repo/my-image"
+}
+
+output "latest_1_x" {
+  value = provider::gcrane::semver_latest(data.gcrane_list.images.tags, "~> 1.0")
+}
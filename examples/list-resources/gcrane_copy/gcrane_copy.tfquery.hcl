@@ -0,0 +1,11 @@
+# Generate configuration for importing mirrored images with:
+# terraform query -generate-config-out=mirrored.tf
+list "gcrane_copy" "mirrored" {
+  provider         = gcrane
+  include_resource = true
+
+  config {
+    repository        = "europe-docker.pkg.dev/my-project/mirror/nginx"
+    source_repository = "nginx"
+  }
+}
@@ -11,4 +11,15 @@ provider "gcrane" {
         }
       }
     EOT
-}
\ No newline at end of file
+}
+# Credentials can also come from ephemeral values, which are never stored
+# in the state or plan files.
+variable "docker_config" {
+  type      = string
+  ephemeral = true
+}
+
+provider "gcrane" {
+  alias         = "ephemeral"
+  docker_config = var.docker_config
+}
@@ -0,0 +1,9 @@
+resource "gcrane_check" "release" {
+  reference       = "europe-west4-docker.pkg.dev/my-project/my-repo/my-image:v1.2.3"
+  expected_digest = "sha256:4a1c4b21597c1b4415bdbecb28a3296c6b5e23ca4f9feeb599860a1dac6a0108"
+
+  required_annotations = {
+    "org.opencontainers.image.source"   = ""
+    "org.opencontainers.image.revision" = "0f3e4c5a"
+  }
+}
@@ -0,0 +1,2 @@
+# The ID is the destination and the digest of the copied image.
+terraform import gcrane_copy.copied_image europe-west4-docker.pkg.dev/my-project/my-repo/my-image:latest@sha256:4a1c4b21597c1b4415bdbecb28a3296c6b5e23ca4f9feeb599860a1dac6a0108
@@ -4,3 +4,21 @@ resource "gcrane_copy" "copied_image" {
   source      = "google/pause"
   destination = "europe-west4-docker.pkg.dev/my-project/my-repo/my-image:latest"
 }
+
+# Credentials for a registry the provider has no access to. They are
+# write-only, so they can come from an ephemeral variable and are never
+# stored in the state.
+variable "partner_password" {
+  type      = string
+  ephemeral = true
+}
+
+resource "gcrane_copy" "partner_image" {
+  source      = "registry.partner.example.com/releases/agent:v1"
+  destination = "europe-west4-docker.pkg.dev/my-project/my-repo/agent:v1"
+
+  auth {
+    username = "mirror"
+    password = var.partner_password
+  }
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ action.Action = &CopyAction{}
+var _ action.ActionWithConfigure = &CopyAction{}
+var _ action.ActionWithModifyPlan = &CopyAction{}
+
+func NewCopyAction() action.Action {
+	return &CopyAction{}
+}
+
+// CopyAction defines the action implementation.
+type CopyAction struct {
+	Client *GcraneData
+}
+
+// CopyActionModel describes the action data model.
+type CopyActionModel struct {
+	Recursive   types.Bool   `tfsdk:"recursive"`
+	Jobs        types.Int64  `tfsdk:"jobs"`
+	Platform    types.String `tfsdk:"platform"`
+	Source      types.String `tfsdk:"source"`
+	Destination types.String `tfsdk:"destination"`
+}
+
+func (a *CopyAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_copy"
+}
+
+func (a *CopyAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Copies container images between repositories when invoked, without tracking the copy in the state",
+		Description:         "Copies container images between repositories when invoked, without tracking the copy in the state",
+		Attributes: map[string]schema.Attribute{
+			"recursive": schema.BoolAttribute{
+				MarkdownDescription: "Recursive copy",
+				Optional:            true,
+			},
+			"jobs": schema.Int64Attribute{
+				MarkdownDescription: "Number of concurrent copies for recursive copies (defaults to the provider `default_jobs`)",
+				Optional:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Copy only the image for this platform (for example `linux/amd64`) when the source is an image index (defaults to the provider `default_platform`, or the whole index). Not supported for recursive copies",
+				Optional:            true,
+			},
+			"source": schema.StringAttribute{
+				MarkdownDescription: "Source for copy",
+				Required:            true,
+			},
+			"destination": schema.StringAttribute{
+				MarkdownDescription: "Destination for copy",
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (a *CopyAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	a.Client = client
+}
+
+func (a *CopyAction) ModifyPlan(ctx context.Context, req action.ModifyPlanRequest, resp *action.ModifyPlanResponse) {
+	if a.Client == nil {
+		return
+	}
+
+	var destination types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("destination"), &destination)...)
+	if resp.Diagnostics.HasError() || destination.IsUnknown() || destination.IsNull() {
+		return
+	}
+
+	if err := a.Client.Destinations.Check(destination.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("destination"),
+			"Destination not allowed",
+			err.Error(),
+		)
+	}
+}
+
+func (a *CopyAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data CopyActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Copying %s to %s", data.Source.ValueString(), data.Destination.ValueString()),
+	})
+
+	r := &CopyResource{Client: a.Client}
+	resp.Diagnostics.Append(r.copy(ctx, &CopyResourceModel{
+		Recursive:   data.Recursive,
+		Jobs:        data.Jobs,
+		Platform:    data.Platform,
+		Source:      data.Source,
+		Destination: data.Destination,
+	})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Copied %s to %s", data.Source.ValueString(), data.Destination.ValueString()),
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccCopyAction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCopyActionConfig(`denied_destinations = ["registry.example.com/**"]`),
+				ExpectError: regexp.MustCompile(`Destination not allowed`),
+			},
+			{
+				Config:      testAccCopyActionConfig(`read_only = true`),
+				ExpectError: regexp.MustCompile(`Provider is read only`),
+			},
+		},
+	})
+}
+
+func testAccCopyActionConfig(providerConfig string) string {
+	return `
+provider "gcrane" {
+  ` + providerConfig + `
+}
+
+action "gcrane_copy" "promote" {
+  config {
+    source      = "google/pause"
+    destination = "registry.example.com/pause:latest"
+  }
+}
+
+resource "terraform_data" "trigger" {
+  lifecycle {
+    action_trigger {
+      events  = [after_create]
+      actions = [action.gcrane_copy.promote]
+    }
+  }
+}
+`
+}
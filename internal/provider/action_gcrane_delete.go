@@ -0,0 +1,553 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ action.Action = &DeleteAction{}
+var _ action.ActionWithConfigure = &DeleteAction{}
+var _ action.ActionWithModifyPlan = &DeleteAction{}
+
+func NewDeleteAction() action.Action {
+	return &DeleteAction{}
+}
+
+// DeleteAction defines the action implementation.
+type DeleteAction struct {
+	Client *GcraneData
+}
+
+// DeleteActionModel describes the action data model.
+type DeleteActionModel struct {
+	Reference     types.String `tfsdk:"reference"`
+	IgnoreMissing types.Bool   `tfsdk:"ignore_missing"`
+	DryRun        types.Bool   `tfsdk:"dry_run"`
+	Recursive     types.Bool   `tfsdk:"recursive"`
+	Confirm       types.String `tfsdk:"confirm_repository"`
+}
+
+func (a *DeleteAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_delete"
+}
+
+func (a *DeleteAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Deletes a tag or a manifest by digest from a registry when invoked, or a whole repository with its child repositories",
+		Description:         "Deletes a tag or a manifest by digest from a registry when invoked, or a whole repository with its child repositories",
+		Attributes: map[string]schema.Attribute{
+			"reference": schema.StringAttribute{
+				MarkdownDescription: "Tag (`repository:tag`) or digest (`repository@sha256:...`) to delete. Deleting a digest deletes the manifest and, depending on the registry, its tags. A repository for recursive deletes",
+				Required:            true,
+			},
+			"ignore_missing": schema.BoolAttribute{
+				MarkdownDescription: "Succeed if the tag or digest does not exist",
+				Optional:            true,
+			},
+			"dry_run": schema.BoolAttribute{
+				MarkdownDescription: "Resolve the tag or digest and report what would be deleted, without deleting anything",
+				Optional:            true,
+			},
+			"recursive": schema.BoolAttribute{
+				MarkdownDescription: "Delete the repository in `reference` and its child repositories, depth-first: all tags and then all manifests of each repository. Fails when a child repository is not an allowed destination. Requires `confirm_repository`",
+				Optional:            true,
+			},
+			"confirm_repository": schema.StringAttribute{
+				MarkdownDescription: "The repository in `reference` again, to confirm a recursive delete",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (a *DeleteAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	a.Client = client
+}
+
+func (a *DeleteAction) ModifyPlan(ctx context.Context, req action.ModifyPlanRequest, resp *action.ModifyPlanResponse) {
+	if a.Client == nil {
+		return
+	}
+
+	var reference types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("reference"), &reference)...)
+	if resp.Diagnostics.HasError() || reference.IsUnknown() || reference.IsNull() {
+		return
+	}
+
+	var recursive types.Bool
+	var confirm types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("recursive"), &recursive)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("confirm_repository"), &confirm)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if recursive.ValueBool() {
+		repo, err := name.NewRepository(reference.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("reference"), "Invalid repository", err.Error())
+			return
+		}
+		if !confirm.IsUnknown() && !confirmsRepository(confirm.ValueString(), reference.ValueString(), repo) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("confirm_repository"),
+				"Recursive delete not confirmed",
+				fmt.Sprintf("Set confirm_repository to %q to delete the repository and all its child repositories.", reference.ValueString()),
+			)
+			return
+		}
+	} else if ref, err := name.ParseReference(reference.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Invalid reference", err.Error())
+		return
+	} else if err := a.checkProtectedTag(ref); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Tag is protected", err.Error())
+		return
+	}
+	if err := a.Client.Destinations.Check(reference.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("reference"),
+			"Destination not allowed",
+			err.Error(),
+		)
+	}
+}
+
+func (a *DeleteAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data DeleteActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, span := a.Client.Tracer.Start(ctx, "delete",
+		attribute.String("gcrane.reference", data.Reference.ValueString()),
+		attribute.Bool("gcrane.recursive", data.Recursive.ValueBool()),
+		attribute.Bool("gcrane.dry_run", data.DryRun.ValueBool()),
+	)
+	defer func() { span.End(ctx, resp.Diagnostics) }()
+
+	if !data.DryRun.ValueBool() {
+		resp.Diagnostics.Append(a.Client.CheckWritable(fmt.Sprintf("delete %s", data.Reference.ValueString()))...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if data.Recursive.ValueBool() {
+		a.invokeRecursive(ctx, data, resp)
+		return
+	}
+
+	ref, err := name.ParseReference(data.Reference.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Invalid reference", err.Error())
+		return
+	}
+	if err := a.checkProtectedTag(ref); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Tag is protected", err.Error())
+		return
+	}
+
+	err = a.Client.Setup(ctx, a.Client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return
+	}
+	defer func() {
+		err := a.Client.Cleanup(ctx, a.Client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	if digest, ok := ref.(name.Digest); ok && a.Client.ProtectedTags.Enabled() {
+		err := a.checkProtectedManifest(ctx, digest)
+		if err != nil && !(data.IgnoreMissing.ValueBool() && isNotFound(err)) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("reference"),
+				"Manifest has a protected tag",
+				registryErrorDetail(err, a.Client.Failures),
+			)
+			return
+		}
+	}
+
+	if data.DryRun.ValueBool() {
+		desc, err := remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(a.Client.Keychain), remote.WithTransport(a.Client.Transport))
+		switch {
+		case err != nil && data.IgnoreMissing.ValueBool() && isNotFound(err):
+			resp.SendProgress(action.InvokeProgressEvent{
+				Message: fmt.Sprintf("%s does not exist", ref),
+			})
+		case err != nil:
+			resp.Diagnostics.AddError(
+				"Could not resolve image",
+				fmt.Sprintf("Error when resolving %s: %s", ref, registryErrorDetail(err, a.Client.Failures)),
+			)
+		default:
+			resp.SendProgress(action.InvokeProgressEvent{
+				Message: fmt.Sprintf("Would delete %s (%s)", ref, desc.Digest),
+			})
+		}
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Deleting %s", ref),
+	})
+
+	err = remote.Delete(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(a.Client.Keychain), remote.WithTransport(a.Client.Transport))
+	if err != nil && data.IgnoreMissing.ValueBool() && isNotFound(err) {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("%s does not exist", ref),
+		})
+		return
+	}
+
+	if auditErr := a.Client.AuditLog.Record(auditEntry{Operation: "delete", Destination: ref.String()}, err); auditErr != nil {
+		resp.Diagnostics.AddWarning("Could not write audit log", auditErr.Error())
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not delete image",
+			fmt.Sprintf("Error when deleting %s: %s", ref, registryErrorDetail(err, a.Client.Failures)),
+		)
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Deleted %s", ref),
+	})
+}
+
+// invokeRecursive deletes a repository and its child repositories.
+func (a *DeleteAction) invokeRecursive(ctx context.Context, data DeleteActionModel, resp *action.InvokeResponse) {
+	repo, err := name.NewRepository(data.Reference.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Invalid repository", err.Error())
+		return
+	}
+	if !confirmsRepository(data.Confirm.ValueString(), data.Reference.ValueString(), repo) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("confirm_repository"),
+			"Recursive delete not confirmed",
+			fmt.Sprintf("Set confirm_repository to %q to delete the repository and all its child repositories.", data.Reference.ValueString()),
+		)
+		return
+	}
+
+	err = a.Client.Setup(ctx, a.Client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return
+	}
+	defer func() {
+		err := a.Client.Cleanup(ctx, a.Client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	progress := func(message string) {
+		resp.SendProgress(action.InvokeProgressEvent{Message: message})
+	}
+	ctx, reporter := a.Client.Progress(ctx, "delete")
+	deleted, err := a.deleteRepository(ctx, repo, data.DryRun.ValueBool(), progress)
+	reporter.Finish()
+	if err != nil && deleted == 0 && data.IgnoreMissing.ValueBool() && isNotFound(err) {
+		progress(fmt.Sprintf("%s does not exist", repo))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not delete repository",
+			fmt.Sprintf("Error when deleting %s: %s", repo, registryErrorDetail(err, a.Client.Failures)),
+		)
+		return
+	}
+
+	if data.DryRun.ValueBool() {
+		progress(fmt.Sprintf("Would delete %d manifests from %s", deleted, repo))
+		return
+	}
+	progress(fmt.Sprintf("Deleted %d manifests from %s", deleted, repo))
+}
+
+// confirmsRepository reports whether confirm confirms the recursive delete
+// of repo, given as reference: either as written or fully qualified.
+func confirmsRepository(confirm, reference string, repo name.Repository) bool {
+	return confirm == reference || confirm == repo.String()
+}
+
+// deleteRepository deletes the child repositories of repo and then repo
+// itself: its tags first and then its manifests, image indexes before the
+// manifests they reference. Child repositories denied by the destination
+// policy fail the delete. It returns the number of deleted manifests.
+// Deletions are reported to the progress reporter of ctx.
+func (a *DeleteAction) deleteRepository(ctx context.Context, repo name.Repository, dryRun bool, progress func(string)) (int, error) {
+	tags, err := listRepository(ctx, repo, listFilter{IncludeUntagged: true}, a.Client.Keychain, a.Client.Transport)
+	if err != nil {
+		return 0, err
+	}
+
+	// Child repositories are checked against the destination policy
+	// before any of them is deleted.
+	children := make([]name.Repository, 0, len(tags.Children))
+	for _, child := range tags.Children {
+		childRepo, err := name.NewRepository(repo.String() + "/" + child)
+		if err != nil {
+			return 0, err
+		}
+		if err := a.Client.Destinations.Check(childRepo.String()); err != nil {
+			return 0, err
+		}
+		children = append(children, childRepo)
+	}
+
+	deleted := 0
+	for _, childRepo := range children {
+		n, err := a.deleteRepository(ctx, childRepo, dryRun, progress)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+
+	opts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(a.Client.Keychain), remote.WithTransport(a.Client.Transport)}
+	manifests := tags.Manifests
+	if len(manifests) == 0 && len(tags.Tags) > 0 {
+		// Registries other than gcr.io and pkg.dev only list tags.
+		manifests = map[string]google.ManifestInfo{}
+		for _, tag := range tags.Tags {
+			desc, err := remote.Head(repo.Tag(tag), opts...)
+			if err != nil {
+				return deleted, err
+			}
+			manifest := manifests[desc.Digest.String()]
+			manifest.MediaType = string(desc.MediaType)
+			manifest.Tags = append(manifest.Tags, tag)
+			manifests[desc.Digest.String()] = manifest
+		}
+	}
+	kept, err := a.protectedManifests(ctx, repo, manifests, progress)
+	if err != nil {
+		return deleted, err
+	}
+	digests := make([]string, 0, len(manifests))
+	for digest := range manifests {
+		if !kept[digest] {
+			digests = append(digests, digest)
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		ii, ij := isIndexMediaType(manifests[digests[i]].MediaType), isIndexMediaType(manifests[digests[j]].MediaType)
+		if ii != ij {
+			return ii
+		}
+		return digests[i] < digests[j]
+	})
+
+	var refs []name.Reference
+	for _, digest := range digests {
+		for _, tag := range manifests[digest].Tags {
+			refs = append(refs, repo.Tag(tag))
+		}
+	}
+	for _, digest := range digests {
+		refs = append(refs, repo.Digest(digest))
+	}
+
+	reporter := progressFrom(ctx)
+	reporter.AddTotal(len(refs))
+	for _, ref := range refs {
+		_, isDigest := ref.(name.Digest)
+		if dryRun {
+			progress(fmt.Sprintf("Would delete %s", ref))
+			if isDigest {
+				deleted++
+			}
+			reporter.Complete(ref.String())
+			continue
+		}
+
+		progress(fmt.Sprintf("Deleting %s", ref))
+		err := remote.Delete(ref, opts...)
+		if !isDigest && isMethodNotAllowed(err) {
+			// Registries that do not delete tags remove them with the
+			// manifest.
+			reporter.Complete(ref.String())
+			continue
+		}
+		if auditErr := a.Client.AuditLog.Record(auditEntry{Operation: "delete", Destination: ref.String()}, err); auditErr != nil {
+			tflog.Warn(ctx, "Could not write audit log", map[string]interface{}{
+				"error": auditErr.Error(),
+			})
+		}
+		if err != nil {
+			return deleted, err
+		}
+		if isDigest {
+			deleted++
+		}
+		reporter.Complete(ref.String())
+	}
+	return deleted, nil
+}
+
+// protectedManifests returns the manifests of repo that are tagged with a
+// protected tag, and the manifests referenced by those that are indexes.
+func (a *DeleteAction) protectedManifests(ctx context.Context, repo name.Repository, manifests map[string]google.ManifestInfo, progress func(string)) (map[string]bool, error) {
+	kept := map[string]bool{}
+	for digest, manifest := range manifests {
+		for _, tag := range manifest.Tags {
+			if pattern, ok := a.Client.ProtectedTags.Match(tag); ok {
+				progress(fmt.Sprintf("Keeping %s, which matches protect_tags pattern %q", repo.Tag(tag), pattern))
+				kept[digest] = true
+			}
+		}
+	}
+	for digest := range kept {
+		if !isIndexMediaType(manifests[digest].MediaType) {
+			continue
+		}
+		index, err := remote.Index(repo.Digest(digest), remote.WithContext(ctx), remote.WithAuthFromKeychain(a.Client.Keychain), remote.WithTransport(a.Client.Transport))
+		if err != nil {
+			return nil, err
+		}
+		im, err := index.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range im.Manifests {
+			kept[child.Digest.String()] = true
+		}
+	}
+	return kept, nil
+}
+
+// checkProtectedTag returns an error when ref is a protected tag.
+func (a *DeleteAction) checkProtectedTag(ref name.Reference) error {
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		return nil
+	}
+	if pattern, ok := a.Client.ProtectedTags.Match(tag.TagStr()); ok {
+		return fmt.Errorf("tag %s matches protect_tags pattern %q", tag.TagStr(), pattern)
+	}
+	return nil
+}
+
+// checkProtectedManifest returns an error when a protected tag points at
+// digest, or at an image index referencing it.
+func (a *DeleteAction) checkProtectedManifest(ctx context.Context, digest name.Digest) error {
+	repo := digest.Context()
+	tags, err := listRepository(ctx, repo, listFilter{IncludeUntagged: true}, a.Client.Keychain, a.Client.Transport)
+	if err != nil {
+		return err
+	}
+	manifests := tags.Manifests
+	if len(manifests) == 0 {
+		// Registries other than gcr.io and pkg.dev only list tags.
+		manifests = map[string]google.ManifestInfo{}
+		for _, tag := range tags.Tags {
+			if _, ok := a.Client.ProtectedTags.Match(tag); !ok {
+				continue
+			}
+			desc, err := remote.Head(repo.Tag(tag), remote.WithContext(ctx), remote.WithAuthFromKeychain(a.Client.Keychain), remote.WithTransport(a.Client.Transport))
+			if err != nil {
+				return err
+			}
+			manifest := manifests[desc.Digest.String()]
+			manifest.MediaType = string(desc.MediaType)
+			manifest.Tags = append(manifest.Tags, tag)
+			manifests[desc.Digest.String()] = manifest
+		}
+	}
+
+	kept, err := a.protectedManifests(ctx, repo, manifests, func(string) {})
+	if err != nil {
+		return err
+	}
+	if !kept[digest.DigestStr()] {
+		return nil
+	}
+	for _, tag := range manifests[digest.DigestStr()].Tags {
+		if pattern, ok := a.Client.ProtectedTags.Match(tag); ok {
+			return fmt.Errorf("%s is tagged %s, which matches protect_tags pattern %q", digest, tag, pattern)
+		}
+	}
+	return fmt.Errorf("%s is referenced by an image index with a protected tag", digest)
+}
+
+// isMethodNotAllowed reports whether err is a registry error for an
+// unsupported operation, like deleting a tag.
+func isMethodNotAllowed(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusMethodNotAllowed
+}
+
+// isNotFound reports whether err is a registry error for a missing
+// repository, tag or manifest.
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
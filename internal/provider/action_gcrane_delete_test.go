@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccDeleteAction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDeleteActionConfig(`denied_destinations = ["registry.example.com/**"]`),
+				ExpectError: regexp.MustCompile(`Destination not allowed`),
+			},
+			{
+				Config:      testAccDeleteActionConfig(`read_only = true`),
+				ExpectError: regexp.MustCompile(`Provider is read only`),
+			},
+			{
+				Config:      testAccDeleteActionConfig(`protect_tags = ["latest", "release-*"]`),
+				ExpectError: regexp.MustCompile(`Tag is protected`),
+			},
+		},
+	})
+}
+
+func TestAccDeleteActionRecursive(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+action "gcrane_delete" "repository" {
+  config {
+    reference          = "registry.example.com/pause"
+    recursive          = true
+    confirm_repository = "registry.example.com/other"
+  }
+}
+
+resource "terraform_data" "trigger" {
+  lifecycle {
+    action_trigger {
+      events  = [after_create]
+      actions = [action.gcrane_delete.repository]
+    }
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`Recursive delete not confirmed`),
+			},
+		},
+	})
+}
+
+func TestDeleteRepositoryChildDestinations(t *testing.T) {
+	registry := newTestListingRegistry(t, 0)
+	registry.SetListing("project", &google.Tags{
+		Children:  []string{"app", "tools"},
+		Manifests: testManifests(1),
+	})
+	registry.SetListing("project/app", &google.Tags{Manifests: testManifests(3)})
+	registry.SetListing("project/tools", &google.Tags{Manifests: testManifests(2)})
+	repo, err := name.NewRepository(registry.Ref("project"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		denied  []string
+		deleted int
+		err     string
+	}{
+		{name: "allowed", deleted: 6},
+		{name: "child denied", denied: []string{registry.Ref("project/tools")}, err: "matches denied_destinations pattern"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := newDestinationPolicy(nil, tc.denied)
+			if err != nil {
+				t.Fatal(err)
+			}
+			a := &DeleteAction{Client: &GcraneData{Keychain: authn.DefaultKeychain, Transport: http.DefaultTransport, Destinations: policy}}
+
+			var messages []string
+			deleted, err := a.deleteRepository(context.Background(), repo, true, func(message string) {
+				messages = append(messages, message)
+			})
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("deleteRepository() error = %v, want %q", err, tc.err)
+				}
+				if len(messages) > 0 {
+					t.Errorf("deleteRepository() deleted %v before checking the child repositories", messages)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("deleteRepository() failed: %v", err)
+			}
+			if deleted != tc.deleted {
+				t.Errorf("deleted %d manifests, want %d", deleted, tc.deleted)
+			}
+		})
+	}
+}
+
+func TestConfirmsRepository(t *testing.T) {
+	for _, tc := range []struct {
+		confirm, reference string
+		want               bool
+	}{
+		{confirm: "myorg/app", reference: "myorg/app", want: true},
+		{confirm: "index.docker.io/myorg/app", reference: "myorg/app", want: true},
+		{confirm: "registry.example.com/app", reference: "registry.example.com/app", want: true},
+		{confirm: "registry.example.com/other", reference: "registry.example.com/app", want: false},
+		{confirm: "", reference: "registry.example.com/app", want: false},
+	} {
+		repo, err := name.NewRepository(tc.reference)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := confirmsRepository(tc.confirm, tc.reference, repo); got != tc.want {
+			t.Errorf("confirmsRepository(%q, %q) = %t, want %t", tc.confirm, tc.reference, got, tc.want)
+		}
+	}
+}
+
+func testAccDeleteActionConfig(providerConfig string) string {
+	return `
+provider "gcrane" {
+  ` + providerConfig + `
+}
+
+action "gcrane_delete" "bad_image" {
+  config {
+    reference = "registry.example.com/pause:latest"
+  }
+}
+
+resource "terraform_data" "trigger" {
+  lifecycle {
+    action_trigger {
+      events  = [after_create]
+      actions = [action.gcrane_delete.bad_image]
+    }
+  }
+}
+`
+}
@@ -0,0 +1,290 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ action.Action = &GcAction{}
+var _ action.ActionWithConfigure = &GcAction{}
+var _ action.ActionWithModifyPlan = &GcAction{}
+
+func NewGcAction() action.Action {
+	return &GcAction{}
+}
+
+// GcAction defines the action implementation.
+type GcAction struct {
+	Client *GcraneData
+}
+
+// GcActionModel describes the action data model.
+type GcActionModel struct {
+	Repository types.String `tfsdk:"repository"`
+	Recursive  types.Bool   `tfsdk:"recursive"`
+	DryRun     types.Bool   `tfsdk:"dry_run"`
+}
+
+func (a *GcAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gc"
+}
+
+func (a *GcAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Deletes the untagged manifests of a repository when invoked, like `gcrane gc`. Manifests referenced by a tagged image index are kept. Only supported for `gcr.io` and `pkg.dev` registries, which can list untagged manifests",
+		Description:         "Deletes the untagged manifests of a repository when invoked, like gcrane gc",
+		Attributes: map[string]schema.Attribute{
+			"repository": schema.StringAttribute{
+				MarkdownDescription: "Repository to clean up",
+				Required:            true,
+			},
+			"recursive": schema.BoolAttribute{
+				MarkdownDescription: "Also clean up the child repositories. Child repositories that are not allowed destinations are skipped with a warning",
+				Optional:            true,
+			},
+			"dry_run": schema.BoolAttribute{
+				MarkdownDescription: "Report the manifests that would be deleted, without deleting anything",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (a *GcAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Action Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	a.Client = client
+}
+
+func (a *GcAction) ModifyPlan(ctx context.Context, req action.ModifyPlanRequest, resp *action.ModifyPlanResponse) {
+	if a.Client == nil {
+		return
+	}
+
+	var repository types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("repository"), &repository)...)
+	if resp.Diagnostics.HasError() || repository.IsUnknown() || repository.IsNull() {
+		return
+	}
+
+	if _, err := name.NewRepository(repository.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("repository"), "Invalid repository", err.Error())
+		return
+	}
+	if err := a.Client.Destinations.Check(repository.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("repository"),
+			"Destination not allowed",
+			err.Error(),
+		)
+	}
+}
+
+func (a *GcAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var data GcActionModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, span := a.Client.Tracer.Start(ctx, "gc",
+		attribute.String("gcrane.repository", data.Repository.ValueString()),
+		attribute.Bool("gcrane.dry_run", data.DryRun.ValueBool()),
+	)
+	defer func() { span.End(ctx, resp.Diagnostics) }()
+
+	if !data.DryRun.ValueBool() {
+		resp.Diagnostics.Append(a.Client.CheckWritable(fmt.Sprintf("garbage collect %s", data.Repository.ValueString()))...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	repo, err := name.NewRepository(data.Repository.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("repository"), "Invalid repository", err.Error())
+		return
+	}
+
+	err = a.Client.Setup(ctx, a.Client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return
+	}
+	defer func() {
+		err := a.Client.Cleanup(ctx, a.Client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	ctx, progress := a.Client.Progress(ctx, "gc")
+	deleted := 0
+	repos := []name.Repository{repo}
+	for len(repos) > 0 {
+		repo, repos = repos[0], repos[1:]
+
+		tags, err := listRepository(ctx, repo, listFilter{IncludeUntagged: true}, a.Client.Keychain, a.Client.Transport)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not list repository",
+				fmt.Sprintf("Error when listing %s: %s", repo, registryErrorDetail(err, a.Client.Failures)),
+			)
+			return
+		}
+		if data.Recursive.ValueBool() {
+			for _, child := range tags.Children {
+				childRepo, err := name.NewRepository(repo.String() + "/" + child)
+				if err != nil {
+					resp.Diagnostics.AddError("Invalid child repository", err.Error())
+					return
+				}
+				if err := a.Client.Destinations.Check(childRepo.String()); err != nil {
+					resp.Diagnostics.AddWarning(
+						"Child repository skipped",
+						fmt.Sprintf("Untagged manifests in %s are not deleted: %s", childRepo, err),
+					)
+					continue
+				}
+				repos = append(repos, childRepo)
+			}
+		}
+
+		digests, err := a.untaggedManifests(ctx, repo, tags.Manifests)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not find untagged manifests",
+				fmt.Sprintf("Error when reading %s: %s", repo, registryErrorDetail(err, a.Client.Failures)),
+			)
+			return
+		}
+		progress.AddTotal(len(digests))
+		for _, digest := range digests {
+			ref := repo.Digest(digest)
+			if data.DryRun.ValueBool() {
+				resp.SendProgress(action.InvokeProgressEvent{
+					Message: fmt.Sprintf("Would delete %s", ref),
+				})
+				deleted++
+				progress.Complete(ref.String())
+				continue
+			}
+			resp.SendProgress(action.InvokeProgressEvent{
+				Message: fmt.Sprintf("Deleting %s", ref),
+			})
+			err := remote.Delete(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(a.Client.Keychain), remote.WithTransport(a.Client.Transport))
+			if auditErr := a.Client.AuditLog.Record(auditEntry{Operation: "gc", Destination: ref.String()}, err); auditErr != nil {
+				resp.Diagnostics.AddWarning("Could not write audit log", auditErr.Error())
+			}
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Could not delete manifest",
+					fmt.Sprintf("Error when deleting %s: %s", ref, registryErrorDetail(err, a.Client.Failures)),
+				)
+				return
+			}
+			deleted++
+			progress.Complete(ref.String())
+		}
+	}
+	progress.Finish()
+
+	if data.DryRun.ValueBool() {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Would delete %d untagged manifests", deleted),
+		})
+		return
+	}
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Deleted %d untagged manifests", deleted),
+	})
+}
+
+// untaggedManifests returns the digests of the untagged manifests of repo
+// that are not referenced by a tagged image index, with image indexes first
+// so they are deleted before the manifests they reference.
+func (a *GcAction) untaggedManifests(ctx context.Context, repo name.Repository, manifests map[string]google.ManifestInfo) ([]string, error) {
+	referenced := map[string]bool{}
+	for digest, m := range manifests {
+		if len(m.Tags) == 0 || !isIndexMediaType(m.MediaType) {
+			continue
+		}
+		index, err := remote.Index(repo.Digest(digest), remote.WithContext(ctx), remote.WithAuthFromKeychain(a.Client.Keychain), remote.WithTransport(a.Client.Transport))
+		if err != nil {
+			return nil, err
+		}
+		im, err := index.IndexManifest()
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range im.Manifests {
+			referenced[child.Digest.String()] = true
+		}
+	}
+
+	var digests []string
+	for digest, m := range manifests {
+		if len(m.Tags) == 0 && !referenced[digest] {
+			digests = append(digests, digest)
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		ii, ij := isIndexMediaType(manifests[digests[i]].MediaType), isIndexMediaType(manifests[digests[j]].MediaType)
+		if ii != ij {
+			return ii
+		}
+		return digests[i] < digests[j]
+	})
+	return digests, nil
+}
+
+// isIndexMediaType reports whether mediaType is an image index or manifest list.
+func isIndexMediaType(mediaType string) bool {
+	return ggcrtypes.MediaType(mediaType).IsIndex()
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccGcAction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccGcActionConfig(`denied_destinations = ["registry.example.com/**"]`),
+				ExpectError: regexp.MustCompile(`Destination not allowed`),
+			},
+			{
+				Config:      testAccGcActionConfig(`read_only = true`),
+				ExpectError: regexp.MustCompile(`Provider is read only`),
+			},
+			{
+				Config:      testAccGcActionConfig(`progress_interval = "often"`),
+				ExpectError: regexp.MustCompile(`Invalid duration`),
+			},
+		},
+	})
+}
+
+func testAccGcActionConfig(providerConfig string) string {
+	return `
+provider "gcrane" {
+  ` + providerConfig + `
+}
+
+action "gcrane_gc" "cleanup" {
+  config {
+    repository = "registry.example.com/pause"
+  }
+}
+
+resource "terraform_data" "trigger" {
+  lifecycle {
+    action_trigger {
+      events  = [after_create]
+      actions = [action.gcrane_gc.cleanup]
+    }
+  }
+}
+`
+}
+
+func TestGcActionSkipsDeniedChildren(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestListingRegistry(t, 0)
+	registry.SetListing("project", &google.Tags{
+		Children:  []string{"app", "tools"},
+		Manifests: testManifests(2, 1),
+	})
+	registry.SetListing("project/app", &google.Tags{Manifests: testManifests(3, 0, 2)})
+	registry.SetListing("project/tools", &google.Tags{Manifests: testManifests(2, 0, 1)})
+
+	policy, err := newDestinationPolicy(nil, []string{registry.Ref("project/tools")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	noop := func(ctx context.Context, data interface{}) error { return nil }
+	a := &GcAction{Client: &GcraneData{
+		Keychain:     authn.DefaultKeychain,
+		Transport:    http.DefaultTransport,
+		Destinations: policy,
+		Setup:        noop,
+		Cleanup:      noop,
+	}}
+
+	var schemaResp action.SchemaResponse
+	a.Schema(ctx, action.SchemaRequest{}, &schemaResp)
+	configType := schemaResp.Schema.Type().TerraformType(ctx)
+	config := tftypes.NewValue(configType, map[string]tftypes.Value{
+		"repository": tftypes.NewValue(tftypes.String, registry.Ref("project")),
+		"recursive":  tftypes.NewValue(tftypes.Bool, true),
+		"dry_run":    tftypes.NewValue(tftypes.Bool, true),
+	})
+
+	var messages []string
+	resp := action.InvokeResponse{SendProgress: func(event action.InvokeProgressEvent) {
+		messages = append(messages, event.Message)
+	}}
+	a.Invoke(ctx, action.InvokeRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config}}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Invoke() failed: %v", resp.Diagnostics)
+	}
+	if got := resp.Diagnostics.WarningsCount(); got != 1 {
+		t.Errorf("got %d warnings, want 1 for the denied child repository: %v", got, resp.Diagnostics)
+	}
+	for _, message := range messages {
+		if strings.Contains(message, registry.Ref("project/tools")) {
+			t.Errorf("denied child repository was cleaned up: %q", message)
+		}
+	}
+	if want := "Would delete 3 untagged manifests"; messages[len(messages)-1] != want {
+		t.Errorf("last message = %q, want %q", messages[len(messages)-1], want)
+	}
+}
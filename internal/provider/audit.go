@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+)
+
+// auditEntry is a single line of the audit log.
+type auditEntry struct {
+	Time         string `json:"time"`
+	User         string `json:"user,omitempty"`
+	Host         string `json:"host,omitempty"`
+	Operation    string `json:"operation"`
+	Source       string `json:"source,omitempty"`
+	SourceDigest string `json:"source_digest,omitempty"`
+	Destination  string `json:"destination"`
+	Result       string `json:"result"`
+	Error        string `json:"error,omitempty"`
+}
+
+// auditLog appends a JSON line per registry mutation to a file.
+type auditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Record appends entry to the audit log, filling in the time and the user
+// and host running Terraform. Recording to a nil audit log does nothing.
+func (a *auditLog) Record(entry auditEntry, err error) error {
+	if a == nil {
+		return nil
+	}
+
+	entry.Time = time.Now().UTC().Format(time.RFC3339)
+	if u, err := user.Current(); err == nil {
+		entry.User = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		entry.Host = host
+	}
+	entry.Result = "success"
+	if err != nil {
+		entry.Result = "failure"
+		entry.Error = err.Error()
+	}
+	line, jsonErr := json.Marshal(entry)
+	if jsonErr != nil {
+		return jsonErr
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	f, fileErr := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if fileErr != nil {
+		return fmt.Errorf("unable to open audit log %s: %w", a.path, fileErr)
+	}
+	defer f.Close()
+	if _, fileErr := f.Write(append(line, '\n')); fileErr != nil {
+		return fmt.Errorf("unable to write audit log %s: %w", a.path, fileErr)
+	}
+	return nil
+}
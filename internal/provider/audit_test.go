@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readAuditLog returns the entries of the audit log at path.
+func readAuditLog(t *testing.T, path string) []auditEntry {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestAuditLogRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit := &auditLog{path: path}
+
+	if err := audit.Record(auditEntry{
+		Operation:    "copy",
+		Source:       "gcr.io/project/app:v1",
+		SourceDigest: "sha256:abc",
+		Destination:  "europe-docker.pkg.dev/project/images/app:v1",
+	}, nil); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+	if err := audit.Record(auditEntry{
+		Operation:   "delete",
+		Destination: "europe-docker.pkg.dev/project/images/app:v1",
+	}, fmt.Errorf("permission denied")); err != nil {
+		t.Fatalf("Record() failed: %v", err)
+	}
+
+	entries := readAuditLog(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	copied, deleted := entries[0], entries[1]
+	if copied.Operation != "copy" || copied.SourceDigest != "sha256:abc" || copied.Result != "success" || copied.Error != "" {
+		t.Errorf("copy entry = %+v", copied)
+	}
+	if deleted.Operation != "delete" || deleted.Result != "failure" || deleted.Error != "permission denied" {
+		t.Errorf("delete entry = %+v", deleted)
+	}
+	if _, err := time.Parse(time.RFC3339, copied.Time); err != nil {
+		t.Errorf("invalid time %q: %v", copied.Time, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("audit log mode = %o, want 600", mode)
+	}
+}
+
+func TestAuditLogConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit := &auditLog{path: path}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := audit.Record(auditEntry{Operation: "copy", Destination: fmt.Sprintf("gcr.io/project/app%d", i)}, nil); err != nil {
+				t.Errorf("Record() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if entries := readAuditLog(t, path); len(entries) != 20 {
+		t.Errorf("got %d entries, want 20", len(entries))
+	}
+}
+
+func TestAuditLogNil(t *testing.T) {
+	var audit *auditLog
+	if err := audit.Record(auditEntry{Operation: "copy"}, nil); err != nil {
+		t.Errorf("Record() on a nil audit log failed: %v", err)
+	}
+}
+
+func TestAuditLogUnwritable(t *testing.T) {
+	audit := &auditLog{path: filepath.Join(t.TempDir(), "missing", "audit.jsonl")}
+	if err := audit.Record(auditEntry{Operation: "copy"}, nil); err == nil {
+		t.Error("Record() succeeded with a missing directory")
+	}
+}
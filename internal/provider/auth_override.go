@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// AuthOverrideModel describes credentials overriding the provider
+// credentials for a single resource.
+type AuthOverrideModel struct {
+	DockerConfig types.String `tfsdk:"docker_config"`
+	Username     types.String `tfsdk:"username"`
+	Password     types.String `tfsdk:"password"`
+	AccessToken  types.String `tfsdk:"access_token"`
+}
+
+// authOverrideDescriptions describe the attributes of an auth block.
+var authOverrideDescriptions = map[string]string{
+	"docker_config": "Contents of a Docker config file (JSON) with credentials for the registries in its `auths`. Other registries use the provider credentials",
+	"username":      "Username for the registries the block applies to, with `password`",
+	"password":      "Password for the registries the block applies to, with `username`",
+	"access_token":  "OAuth access token for the Google registries (gcr.io, pkg.dev) the block applies to",
+}
+
+// authOverrideSecrets are the attributes of an auth block holding
+// credentials.
+var authOverrideSecrets = map[string]bool{
+	"docker_config": true,
+	"password":      true,
+	"access_token":  true,
+}
+
+// authOverrideAttributes returns the attributes of an auth block. The
+// credentials are write-only, so they are never stored in the state.
+func authOverrideAttributes() map[string]schema.Attribute {
+	attributes := make(map[string]schema.Attribute, len(authOverrideDescriptions))
+	for k, description := range authOverrideDescriptions {
+		if authOverrideSecrets[k] {
+			description += ". Write-only: it is not stored in the state, and can be an ephemeral value (requires Terraform 1.11 or later)"
+		}
+		attributes[k] = schema.StringAttribute{
+			MarkdownDescription: description,
+			Optional:            true,
+			Sensitive:           authOverrideSecrets[k],
+			WriteOnly:           authOverrideSecrets[k],
+		}
+	}
+	return attributes
+}
+
+// authOverrideDataSourceAttributes returns the attributes of an auth block
+// of a data source. Data sources cannot have write-only attributes, so the
+// credentials are stored in the state.
+func authOverrideDataSourceAttributes() map[string]datasourceschema.Attribute {
+	attributes := make(map[string]datasourceschema.Attribute, len(authOverrideDescriptions))
+	for k, description := range authOverrideDescriptions {
+		attributes[k] = datasourceschema.StringAttribute{
+			MarkdownDescription: description,
+			Optional:            true,
+			Sensitive:           authOverrideSecrets[k],
+		}
+	}
+	return attributes
+}
+
+// keychain returns a keychain for the credentials of the block, which only
+// apply to the given registries: the access token only to the Google ones.
+// Other registries, and the registries a docker_config has no credentials
+// for, resolve with fallback.
+func (m AuthOverrideModel) keychain(fallback authn.Keychain, registries ...name.Registry) (authn.Keychain, error) {
+	dockerConfig, username, token := m.DockerConfig.ValueString(), m.Username.ValueString(), m.AccessToken.ValueString()
+	set := 0
+	for _, value := range []string{dockerConfig, username, token} {
+		if value != "" {
+			set++
+		}
+	}
+	switch {
+	case set == 0:
+		return nil, fmt.Errorf("one of docker_config, username and access_token must be set")
+	case set > 1:
+		return nil, fmt.Errorf("only one of docker_config, username and access_token can be set")
+	case m.Password.ValueString() != "" && username == "":
+		return nil, fmt.Errorf("password requires username")
+	}
+
+	switch {
+	case dockerConfig != "":
+		var file dockerConfigFile
+		if err := json.Unmarshal([]byte(dockerConfig), &file); err != nil {
+			return nil, fmt.Errorf("unable to parse docker_config: %w", err)
+		}
+		keychain := staticKeychain{}
+		for address, entry := range file.Auths {
+			key, err := registryKey(address)
+			if err != nil {
+				return nil, fmt.Errorf("invalid registry in docker_config: %w", err)
+			}
+			keychain[key] = authn.AuthConfig{
+				Username:      entry.Username,
+				Password:      entry.Password,
+				Auth:          entry.Auth,
+				IdentityToken: entry.IdentityToken,
+				RegistryToken: entry.RegistryToken,
+			}
+		}
+		return authn.NewMultiKeychain(keychain, fallback), nil
+	case username != "":
+		return newRegistryKeychain(registries, authn.FromConfig(authn.AuthConfig{
+			Username: username,
+			Password: m.Password.ValueString(),
+		}), fallback), nil
+	default:
+		google := make([]name.Registry, 0, len(registries))
+		others := make([]string, 0)
+		for _, registry := range registries {
+			if isGoogleRegistry(registry.RegistryStr()) {
+				google = append(google, registry)
+			} else {
+				others = append(others, registry.RegistryStr())
+			}
+		}
+		if len(google) == 0 {
+			return nil, fmt.Errorf("access_token only applies to Google registries (gcr.io, pkg.dev), not to %s", strings.Join(others, ", "))
+		}
+		return newRegistryKeychain(google, authn.FromConfig(authn.AuthConfig{
+			Username: "_token",
+			Password: token,
+		}), fallback), nil
+	}
+}
+
+// registryKeychain resolves the same authenticator for a set of registries,
+// and falls back to another keychain for all other registries.
+type registryKeychain struct {
+	registries map[string]bool
+	auth       authn.Authenticator
+	fallback   authn.Keychain
+}
+
+var _ authn.ContextKeychain = registryKeychain{}
+
+func newRegistryKeychain(registries []name.Registry, auth authn.Authenticator, fallback authn.Keychain) registryKeychain {
+	k := registryKeychain{registries: make(map[string]bool, len(registries)), auth: auth, fallback: fallback}
+	for _, registry := range registries {
+		k.registries[registry.RegistryStr()] = true
+	}
+	return k
+}
+
+func (k registryKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	return k.ResolveContext(context.Background(), target)
+}
+
+func (k registryKeychain) ResolveContext(ctx context.Context, target authn.Resource) (authn.Authenticator, error) {
+	if k.registries[target.RegistryStr()] {
+		return k.auth, nil
+	}
+	return authn.Resolve(ctx, k.fallback, target)
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// resolveAuth returns the credentials keychain resolves for repository.
+func resolveAuth(t *testing.T, keychain authn.Keychain, repository string) authn.AuthConfig {
+	t.Helper()
+
+	repo, err := name.NewRepository(repository)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := keychain.Resolve(repo)
+	if err != nil {
+		t.Fatalf("Resolve(%s) failed: %v", repository, err)
+	}
+	cfg, err := auth.Authorization()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *cfg
+}
+
+// testRegistries parses registry addresses.
+func testRegistries(t *testing.T, addresses ...string) []name.Registry {
+	t.Helper()
+
+	registries := make([]name.Registry, 0, len(addresses))
+	for _, address := range addresses {
+		registry, err := name.NewRegistry(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		registries = append(registries, registry)
+	}
+	return registries
+}
+
+func TestAuthOverrideKeychain(t *testing.T) {
+	provider := authn.AuthConfig{Username: "provider", Password: "provider-secret"}
+	fallback := staticKeychain{
+		"registry.example.com":  provider,
+		"harbor.example.com":    provider,
+		"ghcr.io":               provider,
+		"mirror.example.com":    provider,
+		"europe-docker.pkg.dev": provider,
+		"us-docker.pkg.dev":     provider,
+	}
+
+	tests := []struct {
+		name       string
+		auth       AuthOverrideModel
+		registries []string
+		// want are the expected credentials by repository, the provider
+		// credentials for those not listed.
+		want    map[string]authn.AuthConfig
+		wantErr string
+	}{
+		{
+			name: "username",
+			auth: AuthOverrideModel{
+				Username: types.StringValue("team"),
+				Password: types.StringValue("s3cret"),
+			},
+			registries: []string{"registry.example.com", "harbor.example.com"},
+			want: map[string]authn.AuthConfig{
+				"registry.example.com/project/app": {Username: "team", Password: "s3cret"},
+				"harbor.example.com/library/app":   {Username: "team", Password: "s3cret"},
+				"ghcr.io/owner/app":                provider,
+				"mirror.example.com/project/app":   provider,
+			},
+		},
+		{
+			name:       "access token",
+			auth:       AuthOverrideModel{AccessToken: types.StringValue("ya29.token")},
+			registries: []string{"europe-docker.pkg.dev", "registry.example.com"},
+			want: map[string]authn.AuthConfig{
+				"europe-docker.pkg.dev/project/repo/app": {Username: "_token", Password: "ya29.token"},
+				"us-docker.pkg.dev/project/repo/app":     provider,
+				"registry.example.com/project/app":       provider,
+			},
+		},
+		{
+			name:       "access token without Google registries",
+			auth:       AuthOverrideModel{AccessToken: types.StringValue("ya29.token")},
+			registries: []string{"registry.example.com"},
+			wantErr:    "not to registry.example.com",
+		},
+		{
+			name: "docker config",
+			auth: AuthOverrideModel{
+				DockerConfig: types.StringValue(`{"auths": {"https://harbor.example.com/v1/": {"username": "robot", "password": "token"}}}`),
+			},
+			registries: []string{"registry.example.com"},
+			want: map[string]authn.AuthConfig{
+				"harbor.example.com/library/app":   {Username: "robot", Password: "token"},
+				"registry.example.com/project/app": provider,
+			},
+		},
+		{
+			name:       "nothing set",
+			auth:       AuthOverrideModel{},
+			registries: []string{"registry.example.com"},
+			wantErr:    "must be set",
+		},
+		{
+			name: "password without username",
+			auth: AuthOverrideModel{
+				AccessToken: types.StringValue("ya29.token"),
+				Password:    types.StringValue("s3cret"),
+			},
+			registries: []string{"europe-docker.pkg.dev"},
+			wantErr:    "password requires username",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keychain, err := tt.auth.keychain(fallback, testRegistries(t, tt.registries...)...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("keychain() error = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("keychain() failed: %v", err)
+			}
+			for repository, want := range tt.want {
+				if got := resolveAuth(t, keychain, repository); got != want {
+					t.Errorf("credentials for %s = %+v, want %+v", repository, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// bandwidthLimit throttles uploads and downloads to a number of megabits
+// per second each.
+type bandwidthLimit struct {
+	upload   *rate.Limiter
+	download *rate.Limiter
+}
+
+// newBandwidthLimit returns a limit of mbps megabits per second in each
+// direction.
+func newBandwidthLimit(mbps float64) *bandwidthLimit {
+	bytesPerSecond := mbps * 1000 * 1000 / 8
+	// Allow a tenth of a second worth of data at once, so transfers stay
+	// smooth without too many waits.
+	burst := int(math.Max(math.Ceil(bytesPerSecond/10), 512))
+	return &bandwidthLimit{
+		upload:   rate.NewLimiter(rate.Limit(bytesPerSecond), burst),
+		download: rate.NewLimiter(rate.Limit(bytesPerSecond), burst),
+	}
+}
+
+type bandwidthLimitKey struct{}
+
+// withBandwidthLimit returns a context whose registry requests are throttled
+// to limit instead of the provider max_bandwidth_mbps.
+func withBandwidthLimit(ctx context.Context, limit *bandwidthLimit) context.Context {
+	return context.WithValue(ctx, bandwidthLimitKey{}, limit)
+}
+
+// bandwidthTransport throttles request and response bodies to the limit of
+// the request context, or to the provider limit (nil for no limit).
+type bandwidthTransport struct {
+	inner http.RoundTripper
+	limit *bandwidthLimit
+}
+
+func (t bandwidthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	limit := t.limit
+	if override, ok := ctx.Value(bandwidthLimitKey{}).(*bandwidthLimit); ok {
+		limit = override
+	}
+	if limit == nil {
+		return t.inner.RoundTrip(req)
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		req = req.Clone(ctx)
+		req.Body = &throttledReader{ctx: ctx, inner: req.Body, limiter: limit.upload}
+		if getBody := req.GetBody; getBody != nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				body, err := getBody()
+				if err != nil {
+					return nil, err
+				}
+				return &throttledReader{ctx: ctx, inner: body, limiter: limit.upload}, nil
+			}
+		}
+	}
+
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = &throttledReader{ctx: ctx, inner: resp.Body, limiter: limit.download}
+	return resp, nil
+}
+
+// throttledReader waits for the limiter before handing out the bytes read.
+type throttledReader struct {
+	ctx     context.Context
+	inner   io.ReadCloser
+	limiter *rate.Limiter
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	if burst := r.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := r.inner.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (r *throttledReader) Close() error {
+	return r.inner.Close()
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// blobMounts counts the blobs mounted from another repository of the same
+// registry instead of being uploaded. go-containerregistry mounts blobs by
+// itself when the source and the destination of a copy share a registry.
+type blobMounts struct {
+	count atomic.Int64
+}
+
+type blobMountsKey struct{}
+
+// withBlobMounts returns a context whose mounted blobs are counted in mounts.
+func withBlobMounts(ctx context.Context, mounts *blobMounts) context.Context {
+	return context.WithValue(ctx, blobMountsKey{}, mounts)
+}
+
+// blobMountTransport counts successful cross-repository blob mounts in the
+// blobMounts of the request context.
+type blobMountTransport struct {
+	inner http.RoundTripper
+}
+
+func (t blobMountTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusCreated || req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/blobs/uploads/") {
+		return resp, err
+	}
+	// Registries also answer 201 to a mount without a source repository
+	// when the blob already exists, which is not a mount.
+	query := req.URL.Query()
+	if query.Get("mount") == "" || query.Get("from") == "" {
+		return resp, err
+	}
+	if mounts, ok := req.Context().Value(blobMountsKey{}).(*blobMounts); ok {
+		mounts.count.Add(1)
+	}
+	return resp, err
+}
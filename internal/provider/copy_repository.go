@@ -0,0 +1,200 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/gcrane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// copyFailure is a repository or image a recursive copy failed for.
+type copyFailure struct {
+	Reference string
+	Err       error
+}
+
+// copyTask copies a manifest with its missing tags to another repository.
+type copyTask struct {
+	digest string
+	tags   []string
+	from   name.Repository
+	to     name.Repository
+}
+
+// copyRepository copies src and its child repositories to dst like
+// gcrane.CopyRepository, but carries on past the repositories and images
+// that fail to copy and returns them, sorted by reference. Copied images
+// are reported to the progress reporter of ctx.
+func copyRepository(ctx context.Context, src, dst string, jobs int, keychain authn.Keychain, base http.RoundTripper) ([]copyFailure, error) {
+	srcRepo, err := name.NewRepository(src)
+	if err != nil {
+		return nil, fmt.Errorf("parsing repo %q: %w", src, err)
+	}
+	dstRepo, err := name.NewRepository(dst)
+	if err != nil {
+		return nil, fmt.Errorf("parsing repo %q: %w", dst, err)
+	}
+
+	var failures []copyFailure
+	var tasks []copyTask
+	repos := []name.Repository{srcRepo}
+	for len(repos) > 0 {
+		var repo name.Repository
+		repo, repos = repos[0], repos[1:]
+
+		want, err := listRepository(ctx, repo, listFilter{IncludeUntagged: true}, keychain, base)
+		if err != nil {
+			failures = append(failures, copyFailure{Reference: repo.String(), Err: err})
+			continue
+		}
+		for _, child := range want.Children {
+			childRepo, err := name.NewRepository(repo.String() + "/" + child)
+			if err != nil {
+				failures = append(failures, copyFailure{Reference: repo.String() + "/" + child, Err: err})
+				continue
+			}
+			repos = append(repos, childRepo)
+		}
+
+		// Registries other than gcr.io and pkg.dev only list tags.
+		if len(want.Manifests) == 0 && len(want.Tags) > 0 {
+			want.Manifests = map[string]google.ManifestInfo{}
+			for _, tag := range want.Tags {
+				desc, err := remote.Head(repo.Tag(tag), remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain), remote.WithTransport(base))
+				if err != nil {
+					failures = append(failures, copyFailure{Reference: repo.Tag(tag).String(), Err: err})
+					continue
+				}
+				manifest := want.Manifests[desc.Digest.String()]
+				manifest.Tags = append(manifest.Tags, tag)
+				want.Manifests[desc.Digest.String()] = manifest
+			}
+		}
+
+		to, err := name.NewRepository(strings.Replace(repo.String(), srcRepo.String(), dstRepo.String(), 1), name.StrictValidation)
+		if err != nil {
+			failures = append(failures, copyFailure{Reference: repo.String(), Err: err})
+			continue
+		}
+		have := map[string]google.ManifestInfo{}
+		existing, err := listRepository(ctx, to, listFilter{IncludeUntagged: true}, keychain, base)
+		if err != nil && !isNotFound(err) {
+			failures = append(failures, copyFailure{Reference: to.String(), Err: err})
+			continue
+		}
+		if err == nil {
+			have = existing.Manifests
+		}
+
+		for digest, manifest := range want.Manifests {
+			tags := manifest.Tags
+			if current, ok := have[digest]; ok {
+				tags = missingTags(manifest.Tags, current.Tags)
+				if len(tags) == 0 {
+					continue
+				}
+			}
+			tasks = append(tasks, copyTask{digest: digest, tags: tags, from: repo, to: to})
+		}
+	}
+
+	progress := progressFrom(ctx)
+	progress.AddTotal(len(tasks))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan copyTask)
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for task := range queue {
+				if err := task.copy(ctx, keychain, base); err != nil {
+					mu.Lock()
+					failures = append(failures, copyFailure{Reference: task.reference(), Err: err})
+					mu.Unlock()
+				}
+				progress.Complete(task.reference())
+			}
+		}()
+	}
+	for _, task := range tasks {
+		queue <- task
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Reference < failures[j].Reference
+	})
+	return failures, ctx.Err()
+}
+
+// copy copies the manifest of a task by its first tag, and adds the other
+// tags. Untagged manifests are copied by digest.
+func (t copyTask) copy(ctx context.Context, keychain authn.Keychain, base http.RoundTripper) error {
+	if len(t.tags) == 0 {
+		return gcrane.Copy(t.from.Digest(t.digest).String(), t.to.Digest(t.digest).String(), gcrane.WithContext(ctx), gcrane.WithKeychain(keychain), gcrane.WithTransport(base))
+	}
+
+	err := gcrane.Copy(t.from.Tag(t.tags[0]).String(), t.to.Tag(t.tags[0]).String(), gcrane.WithContext(ctx), gcrane.WithKeychain(keychain), gcrane.WithTransport(base))
+	if err != nil || len(t.tags) == 1 {
+		return err
+	}
+
+	opts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(keychain), remote.WithTransport(base)}
+	desc, err := remote.Get(t.from.Digest(t.digest), opts...)
+	if err != nil {
+		return err
+	}
+	for _, tag := range t.tags[1:] {
+		if err := remote.Tag(t.to.Tag(tag), desc, opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reference returns the source reference of a task, by tag if it has one.
+func (t copyTask) reference() string {
+	if len(t.tags) > 0 {
+		return t.from.Tag(t.tags[0]).String()
+	}
+	return t.from.Digest(t.digest).String()
+}
+
+// missingTags returns the tags in want that are not in have.
+func missingTags(want, have []string) []string {
+	existing := make(map[string]bool, len(have))
+	for _, tag := range have {
+		existing[tag] = true
+	}
+	var missing []string
+	for _, tag := range want {
+		if !existing[tag] {
+			missing = append(missing, tag)
+		}
+	}
+	return missing
+}
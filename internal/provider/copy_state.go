@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// copyStateMigration migrates the state of a copy, decoded from JSON, from
+// one schema version to the next.
+type copyStateMigration func(ctx context.Context, state map[string]interface{}) error
+
+// copyStateMigrations migrate the state of a copy from each earlier schema
+// version, in order: the first migrates version 0 states to version 1. The
+// schema version of gcrane_copy is the number of migrations, so changing
+// the state of existing copies only takes appending a migration.
+var copyStateMigrations = []copyStateMigration{
+	migrateCopyIdDigest,
+}
+
+// copySchemaVersion returns the current schema version of gcrane_copy.
+func copySchemaVersion() int64 {
+	return int64(len(copyStateMigrations))
+}
+
+// UpgradeState returns an upgrader for each earlier schema version, which
+// applies the migrations from that version on.
+func (r *CopyResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	upgraders := make(map[int64]resource.StateUpgrader, len(copyStateMigrations))
+	for version := range copyStateMigrations {
+		migrations := copyStateMigrations[version:]
+		upgraders[int64(version)] = resource.StateUpgrader{
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				resp.Diagnostics.Append(upgradeCopyState(ctx, req.RawState, migrations, &resp.State)...)
+			},
+		}
+	}
+	return upgraders
+}
+
+// migrateCopyIdDigest adds the digest of the copied image to the ID of
+// version 0 copies, which was the destination. Copies made before digests
+// were recorded have none, so their ID is set when Read resolves the digest
+// of their destination.
+func migrateCopyIdDigest(ctx context.Context, state map[string]interface{}) error {
+	destination, _ := state["destination"].(string)
+	digest, _ := state["digest"].(string)
+	if destination != "" && digest != "" {
+		state["id"] = copyId(destination, digest)
+	}
+	return nil
+}
+
+// upgradeCopyState applies migrations to the raw state of a copy, and
+// stores the result in state, which has the current schema.
+func upgradeCopyState(ctx context.Context, raw *tfprotov6.RawState, migrations []copyStateMigration, state *tfsdk.State) (diags diag.Diagnostics) {
+	if raw == nil || raw.JSON == nil {
+		diags.AddError("Unable to Upgrade Resource State", "The saved state of the copy has no JSON data to upgrade.")
+		return diags
+	}
+
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw.JSON))
+	// Numbers like jobs are kept as is rather than converted to floats.
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		diags.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Unable to parse the saved state of the copy: %s", err))
+		return diags
+	}
+	for _, migrate := range migrations {
+		if err := migrate(ctx, values); err != nil {
+			diags.AddError("Unable to Upgrade Resource State", fmt.Sprintf("Unable to upgrade the saved state of the copy: %s", err))
+			return diags
+		}
+	}
+
+	upgraded, err := json.Marshal(values)
+	if err != nil {
+		diags.AddError("Unable to Upgrade Resource State", err.Error())
+		return diags
+	}
+	// Attributes removed from the schema are dropped, like the framework
+	// does for states of the current version.
+	value, err := (&tfprotov6.RawState{JSON: upgraded}).UnmarshalWithOpts(state.Schema.Type().TerraformType(ctx), tfprotov6.UnmarshalOpts{
+		ValueFromJSONOpts: tftypes.ValueFromJSONOpts{IgnoreUndefinedAttributes: true},
+	})
+	if err != nil {
+		diags.AddError("Unable to Upgrade Resource State", fmt.Sprintf("The upgraded state of the copy does not match the schema: %s", err))
+		return diags
+	}
+	state.Raw = value
+	return diags
+}
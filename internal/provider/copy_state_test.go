@@ -0,0 +1,184 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestUpgradeCopyState(t *testing.T) {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	(&CopyResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	raw := &tfprotov6.RawState{JSON: []byte(`{
+		"id": "example.com/target/image:latest",
+		"source": "example.com/source/image:latest",
+		"destination": "example.com/target/image:latest",
+		"jobs": 9007199254740993,
+		"removed_attribute": true
+	}`)}
+	migrations := []copyStateMigration{
+		func(ctx context.Context, state map[string]interface{}) error {
+			state["id"] = fmt.Sprintf("%s (migrated)", state["id"])
+			return nil
+		},
+		func(ctx context.Context, state map[string]interface{}) error {
+			state["recursive"] = false
+			return nil
+		},
+	}
+
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := upgradeCopyState(ctx, raw, migrations, &state); diags.HasError() {
+		t.Fatalf("upgrading state: %v", diags)
+	}
+
+	var data CopyResourceModel
+	if diags := state.Get(ctx, &data); diags.HasError() {
+		t.Fatalf("reading upgraded state: %v", diags)
+	}
+	if got, want := data.Id.ValueString(), "example.com/target/image:latest (migrated)"; got != want {
+		t.Errorf("id = %q, want %q", got, want)
+	}
+	if got, want := data.Jobs.ValueInt64(), int64(9007199254740993); got != want {
+		t.Errorf("jobs = %d, want %d", got, want)
+	}
+	if !data.Recursive.Equal(types.BoolValue(false)) {
+		t.Errorf("recursive = %s, want false", data.Recursive)
+	}
+
+	failing := []copyStateMigration{
+		func(ctx context.Context, state map[string]interface{}) error {
+			return fmt.Errorf("unsupported state")
+		},
+	}
+	state = tfsdk.State{Schema: schemaResp.Schema}
+	if diags := upgradeCopyState(ctx, raw, failing, &state); !diags.HasError() {
+		t.Errorf("upgrading state with a failing migration did not fail")
+	}
+	if state.Raw.Type() != nil {
+		t.Errorf("failed upgrade set the state to %s", state.Raw)
+	}
+}
+
+func TestCopyUpgradeStateVersions(t *testing.T) {
+	upgraders := (&CopyResource{}).UpgradeState(context.Background())
+	if got, want := int64(len(upgraders)), copySchemaVersion(); got != want {
+		t.Fatalf("got %d state upgraders, want one per earlier schema version (%d)", got, want)
+	}
+	for version := int64(0); version < copySchemaVersion(); version++ {
+		if _, ok := upgraders[version]; !ok {
+			t.Errorf("no state upgrader for schema version %d", version)
+		}
+	}
+}
+
+func TestCopyUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	var schemaResp resource.SchemaResponse
+	(&CopyResource{}).Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	// Version 0 states have no digest, so the ID stays the destination until
+	// the copy is read.
+	tests := []string{
+		`{"id": "example.com/target/image:latest", "source": "example.com/source/image:latest", "destination": "example.com/target/image:latest", "recursive": false}`,
+		`{"id": "example.com/target", "source": "example.com/source", "destination": "example.com/target", "recursive": true}`,
+	}
+	upgrader := (&CopyResource{}).UpgradeState(ctx)[0]
+	for _, state := range tests {
+		req := resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: []byte(state)}}
+		resp := resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+		upgrader.StateUpgrader(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("upgrading %s: %v", state, resp.Diagnostics)
+		}
+		var data CopyResourceModel
+		if diags := resp.State.Get(ctx, &data); diags.HasError() {
+			t.Fatalf("reading upgraded state: %v", diags)
+		}
+		if got, want := data.Id.ValueString(), data.Destination.ValueString(); got != want {
+			t.Errorf("upgrading %s: id = %q, want %q", state, got, want)
+		}
+		if !data.Digest.IsNull() {
+			t.Errorf("upgrading %s: digest = %s, want null", state, data.Digest)
+		}
+	}
+}
+
+func TestCopyReadUnrecordedDigest(t *testing.T) {
+	ctx := context.Background()
+	registry := newTestRegistry(t)
+	digest := registry.SeedRandomImage(t, "target/image:latest")
+	destination := registry.Ref("target/image:latest")
+
+	p := New("test")()
+	var providerSchema provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &providerSchema)
+	providerType := providerSchema.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(providerType.AttributeTypes))
+	for k, attrType := range providerType.AttributeTypes {
+		values[k] = tftypes.NewValue(attrType, nil)
+	}
+	var configured provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: providerSchema.Schema, Raw: tftypes.NewValue(providerType, values)}}, &configured)
+	if configured.Diagnostics.HasError() {
+		t.Fatalf("configuring the provider: %v", configured.Diagnostics)
+	}
+	r := &CopyResource{}
+	var configureResp resource.ConfigureResponse
+	r.Configure(ctx, resource.ConfigureRequest{ProviderData: configured.ResourceData}, &configureResp)
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	var identityResp resource.IdentitySchemaResponse
+	r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, &identityResp)
+
+	// A copy made before digests were recorded.
+	raw := &tfprotov6.RawState{JSON: []byte(fmt.Sprintf(`{"id": %[1]q, "source": "example.com/source/image:latest", "destination": %[1]q, "recursive": false}`, destination))}
+	upgraded := resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+	r.UpgradeState(ctx)[0].StateUpgrader(ctx, resource.UpgradeStateRequest{RawState: raw}, &upgraded)
+	if upgraded.Diagnostics.HasError() {
+		t.Fatalf("upgrading state: %v", upgraded.Diagnostics)
+	}
+
+	identityType := identityResp.IdentitySchema.Type().TerraformType(ctx)
+	resp := resource.ReadResponse{
+		State:    upgraded.State,
+		Identity: &tfsdk.ResourceIdentity{Schema: identityResp.IdentitySchema, Raw: tftypes.NewValue(identityType, nil)},
+	}
+	r.Read(ctx, resource.ReadRequest{State: upgraded.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Read() failed: %v", resp.Diagnostics)
+	}
+	var data CopyResourceModel
+	if diags := resp.State.Get(ctx, &data); diags.HasError() {
+		t.Fatalf("reading state: %v", diags)
+	}
+	if got := data.Digest.ValueString(); got != digest {
+		t.Errorf("digest = %q, want the digest of the destination %q", got, digest)
+	}
+	if got, want := data.Id.ValueString(), destination+"@"+digest; got != want {
+		t.Errorf("id = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GcraneArtifactRegistryRepositoriesDataSource{}
+
+func NewGcraneArtifactRegistryRepositoriesDataSource() datasource.DataSource {
+	return &GcraneArtifactRegistryRepositoriesDataSource{}
+}
+
+// GcraneArtifactRegistryRepositoriesDataSource defines the data source implementation.
+type GcraneArtifactRegistryRepositoriesDataSource struct {
+	Client *GcraneData
+}
+
+// GcraneArtifactRegistryRepositoryModel describes an Artifact Registry
+// repository.
+type GcraneArtifactRegistryRepositoryModel struct {
+	RepositoryId types.String `tfsdk:"repository_id"`
+	Name         types.String `tfsdk:"name"`
+	Format       types.String `tfsdk:"format"`
+	Mode         types.String `tfsdk:"mode"`
+	Description  types.String `tfsdk:"description"`
+	Labels       types.Map    `tfsdk:"labels"`
+	SizeBytes    types.Int64  `tfsdk:"size_bytes"`
+	CreateTime   types.String `tfsdk:"create_time"`
+	UpdateTime   types.String `tfsdk:"update_time"`
+	Registry     types.String `tfsdk:"registry"`
+}
+
+// GcraneArtifactRegistryRepositoriesDataSourceModel describes the data source data model.
+type GcraneArtifactRegistryRepositoriesDataSourceModel struct {
+	Project      types.String `tfsdk:"project"`
+	Location     types.String `tfsdk:"location"`
+	Format       types.String `tfsdk:"format"`
+	Repositories types.List   `tfsdk:"repositories"`
+}
+
+func (o GcraneArtifactRegistryRepositoryModel) AttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"repository_id": types.StringType,
+		"name":          types.StringType,
+		"format":        types.StringType,
+		"mode":          types.StringType,
+		"description":   types.StringType,
+		"labels": types.MapType{
+			ElemType: types.StringType,
+		},
+		"size_bytes":  types.Int64Type,
+		"create_time": types.StringType,
+		"update_time": types.StringType,
+		"registry":    types.StringType,
+	}
+}
+
+func (d *GcraneArtifactRegistryRepositoriesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_artifact_registry_repositories"
+}
+
+func (d *GcraneArtifactRegistryRepositoriesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Lists the Artifact Registry repositories of a project in a location with the Artifact Registry API, using the provider Google credentials",
+		Description:         "Lists the Artifact Registry repositories of a project in a location",
+		Attributes: map[string]schema.Attribute{
+			"project": schema.StringAttribute{
+				MarkdownDescription: "Project ID (for example `my-project` or `example.com:my-project`)",
+				Required:            true,
+			},
+			"location": schema.StringAttribute{
+				MarkdownDescription: "Region or multi-region of the repositories (for example `europe-west4` or `us`)",
+				Required:            true,
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "Only return repositories of this format (defaults to `DOCKER`). Set to an empty string to return all repositories",
+				Optional:            true,
+			},
+			"repositories": schema.ListNestedAttribute{
+				MarkdownDescription: "Repositories, sorted by repository ID",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"repository_id": schema.StringAttribute{
+							MarkdownDescription: "Repository ID",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Resource name (`projects/PROJECT/locations/LOCATION/repositories/REPOSITORY`)",
+							Computed:            true,
+						},
+						"format": schema.StringAttribute{
+							MarkdownDescription: "Format of the repository, for example `DOCKER`",
+							Computed:            true,
+						},
+						"mode": schema.StringAttribute{
+							MarkdownDescription: "Mode of the repository: `STANDARD_REPOSITORY`, `VIRTUAL_REPOSITORY` or `REMOTE_REPOSITORY`",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Description of the repository",
+							Computed:            true,
+						},
+						"labels": schema.MapAttribute{
+							MarkdownDescription: "Labels of the repository",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+						"size_bytes": schema.Int64Attribute{
+							MarkdownDescription: "Size of the repository in bytes",
+							Computed:            true,
+						},
+						"create_time": schema.StringAttribute{
+							MarkdownDescription: "Creation time (RFC 3339)",
+							Computed:            true,
+						},
+						"update_time": schema.StringAttribute{
+							MarkdownDescription: "Last update time (RFC 3339)",
+							Computed:            true,
+						},
+						"registry": schema.StringAttribute{
+							MarkdownDescription: "Repository address for images (`LOCATION-docker.pkg.dev/PROJECT/REPOSITORY`), for Docker repositories",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *GcraneArtifactRegistryRepositoriesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.Client = client
+}
+
+func (d *GcraneArtifactRegistryRepositoriesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GcraneArtifactRegistryRepositoriesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, location := data.Project.ValueString(), data.Location.ValueString()
+	if !projectIdRegexp.MatchString(project) {
+		resp.Diagnostics.AddAttributeError(path.Root("project"), "Invalid project", fmt.Sprintf("Invalid project ID %q.", project))
+	}
+	if !locationRegexp.MatchString(location) {
+		resp.Diagnostics.AddAttributeError(path.Root("location"), "Invalid location", fmt.Sprintf("Invalid location %q.", location))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	format := "DOCKER"
+	if !data.Format.IsNull() {
+		format = data.Format.ValueString()
+	}
+
+	client, err := d.Client.googleAPIClient(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to find Google credentials", err.Error())
+		return
+	}
+	repositories, err := listArtifactRegistryRepositories(ctx, client, project, location)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to list repositories",
+			fmt.Sprintf("Failed to list the repositories of project %s in %s: %s", project, location, err),
+		)
+		return
+	}
+
+	models := []GcraneArtifactRegistryRepositoryModel{}
+	for _, repository := range repositories {
+		if format != "" && !strings.EqualFold(repository.Format, format) {
+			continue
+		}
+		id := repository.Name[strings.LastIndex(repository.Name, "/")+1:]
+		labels := repository.Labels
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labelsValue, diags := types.MapValueFrom(ctx, types.StringType, labels)
+		resp.Diagnostics.Append(diags...)
+		model := GcraneArtifactRegistryRepositoryModel{
+			RepositoryId: types.StringValue(id),
+			Name:         types.StringValue(repository.Name),
+			Format:       types.StringValue(repository.Format),
+			Mode:         types.StringValue(repository.Mode),
+			Description:  types.StringValue(repository.Description),
+			Labels:       labelsValue,
+			SizeBytes:    types.Int64Value(repository.SizeBytes),
+			CreateTime:   stringOrNull(repository.CreateTime),
+			UpdateTime:   stringOrNull(repository.UpdateTime),
+			Registry:     types.StringNull(),
+		}
+		if repository.Format == "DOCKER" {
+			// Domain scoped projects use a slash instead of a colon in the path.
+			model.Registry = types.StringValue(fmt.Sprintf("%s-docker.pkg.dev/%s/%s", location, strings.Replace(project, ":", "/", 1), id))
+		}
+		models = append(models, model)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].RepositoryId.ValueString() < models[j].RepositoryId.ValueString()
+	})
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: GcraneArtifactRegistryRepositoryModel{}.AttributeTypes()}, models)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Repositories = list
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+// newTestArtifactRegistryAPI serves two pages of repositories for
+// my-project in europe-west4, and points the provider at it. Quota can only
+// be billed to my-billing-project.
+func newTestArtifactRegistryAPI(t *testing.T) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":401,"message":"Request had invalid authentication credentials.","status":"UNAUTHENTICATED"}}`))
+			return
+		}
+		if project := r.Header.Get("X-Goog-User-Project"); project != "" && project != "my-billing-project" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":{"code":403,"message":"Caller does not have required permission to use project ` + project + `.","status":"PERMISSION_DENIED"}}`))
+			return
+		}
+		if r.URL.Path != "/projects/my-project/locations/europe-west4/repositories" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":404,"message":"Not found.","status":"NOT_FOUND"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("pageToken") == "" {
+			w.Write([]byte(`{"repositories":[{"name":"projects/my-project/locations/europe-west4/repositories/mirror","format":"DOCKER","mode":"STANDARD_REPOSITORY","labels":{"team":"platform"},"sizeBytes":"1024"},{"name":"projects/my-project/locations/europe-west4/repositories/npm","format":"NPM","mode":"STANDARD_REPOSITORY"}],"nextPageToken":"page-2"}`))
+			return
+		}
+		w.Write([]byte(`{"repositories":[{"name":"projects/my-project/locations/europe-west4/repositories/base-images","format":"DOCKER","mode":"REMOTE_REPOSITORY"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	endpoint := artifactRegistryEndpoint
+	artifactRegistryEndpoint = server.URL
+	t.Cleanup(func() { artifactRegistryEndpoint = endpoint })
+}
+
+func TestAccArtifactRegistryRepositoriesDataSource(t *testing.T) {
+	newTestArtifactRegistryAPI(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccArtifactRegistryRepositoriesDataSourceConfig(``),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_artifact_registry_repositories.docker",
+						tfjsonpath.New("repositories"),
+						knownvalue.ListSizeExact(2),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_artifact_registry_repositories.docker",
+						tfjsonpath.New("repositories").AtSliceIndex(0).AtMapKey("registry"),
+						knownvalue.StringExact("europe-west4-docker.pkg.dev/my-project/base-images"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_artifact_registry_repositories.docker",
+						tfjsonpath.New("repositories").AtSliceIndex(1).AtMapKey("size_bytes"),
+						knownvalue.Int64Exact(1024),
+					),
+				},
+			},
+			{
+				Config: testAccArtifactRegistryRepositoriesDataSourceConfig(`billing_project = "my-billing-project"`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_artifact_registry_repositories.docker",
+						tfjsonpath.New("repositories"),
+						knownvalue.ListSizeExact(2),
+					),
+				},
+			},
+			{
+				Config:      testAccArtifactRegistryRepositoriesDataSourceConfig(`billing_project = "other-project"`),
+				ExpectError: regexp.MustCompile(`does not have required permission to use project other-project`),
+			},
+		},
+	})
+}
+
+func testAccArtifactRegistryRepositoriesDataSourceConfig(providerConfig string) string {
+	return `
+provider "gcrane" {
+  access_token = "test-token"
+  ` + providerConfig + `
+}
+
+data "gcrane_artifact_registry_repositories" "docker" {
+  project  = "my-project"
+  location = "europe-west4"
+}
+`
+}
@@ -0,0 +1,286 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GcraneBaseImageDataSource{}
+
+func NewGcraneBaseImageDataSource() datasource.DataSource {
+	return &GcraneBaseImageDataSource{}
+}
+
+// GcraneBaseImageDataSource defines the data source implementation.
+type GcraneBaseImageDataSource struct {
+	Client *GcraneData
+}
+
+// GcraneBaseImageDataSourceModel describes the data source data model.
+type GcraneBaseImageDataSourceModel struct {
+	Reference  types.String `tfsdk:"reference"`
+	Platform   types.String `tfsdk:"platform"`
+	Candidates types.List   `tfsdk:"candidates"`
+	Name       types.String `tfsdk:"name"`
+	Digest     types.String `tfsdk:"digest"`
+	DetectedBy types.String `tfsdk:"detected_by"`
+}
+
+// Annotations of the base image, see
+// https://github.com/opencontainers/image-spec/blob/main/annotations.md.
+const (
+	baseImageNameAnnotation   = "org.opencontainers.image.base.name"
+	baseImageDigestAnnotation = "org.opencontainers.image.base.digest"
+)
+
+func (d *GcraneBaseImageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_base_image"
+}
+
+func (d *GcraneBaseImageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Reports the base image an image was built from, using the `org.opencontainers.image.base.name` and `org.opencontainers.image.base.digest` annotations. Without annotations, the base image is the candidate whose layers the image starts with, preferring the candidate with the most layers. Only manifests are read",
+		Description:         "Reports the base image an image was built from, from its annotations or by matching layers against candidates",
+		Attributes: map[string]schema.Attribute{
+			"reference": schema.StringAttribute{
+				MarkdownDescription: "Image tag or digest reference",
+				Required:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Image to inspect (for example `linux/arm64`) when the reference or a candidate is an image index (defaults to the provider `default_platform`, or `linux/amd64`)",
+				Optional:            true,
+			},
+			"candidates": schema.ListAttribute{
+				MarkdownDescription: "Base image references to match the layers of the image against when it has no base image annotations",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Base image reference, or null when no base image was found",
+				Computed:            true,
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the base image, when known",
+				Computed:            true,
+			},
+			"detected_by": schema.StringAttribute{
+				MarkdownDescription: "How the base image was found: `annotation` or `layers`. Null when no base image was found",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *GcraneBaseImageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.Client = client
+}
+
+func (d *GcraneBaseImageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GcraneBaseImageDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ref, err := name.ParseReference(data.Reference.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Invalid reference", err.Error())
+		return
+	}
+	var candidateNames []string
+	resp.Diagnostics.Append(data.Candidates.ElementsAs(ctx, &candidateNames, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	candidates := make([]name.Reference, 0, len(candidateNames))
+	for _, candidate := range candidateNames {
+		candidateRef, err := name.ParseReference(candidate)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("candidates"), "Invalid reference", err.Error())
+			return
+		}
+		candidates = append(candidates, candidateRef)
+	}
+	platform := d.Client.DefaultPlatform
+	if !data.Platform.IsNull() {
+		platform, err = v1.ParsePlatform(data.Platform.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("platform"), "Invalid platform", fmt.Sprintf("Unable to parse platform %q: %s", data.Platform.ValueString(), err))
+			return
+		}
+	}
+
+	err = d.Client.Setup(ctx, d.Client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return
+	}
+	defer func() {
+		err := d.Client.Cleanup(ctx, d.Client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	opts := []remote.Option{
+		remote.WithAuthFromKeychain(d.Client.Keychain),
+		remote.WithTransport(d.Client.Transport),
+		remote.WithContext(ctx),
+	}
+	if platform != nil {
+		opts = append(opts, remote.WithPlatform(*platform))
+	}
+	base, err := detectBaseImage(ref, candidates, opts...)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to detect base image",
+			fmt.Sprintf("Failed to detect the base image of %s: %s", ref, registryErrorDetail(err, d.Client.Failures)),
+		)
+		return
+	}
+
+	data.Name = types.StringNull()
+	data.Digest = types.StringNull()
+	data.DetectedBy = types.StringNull()
+	if base.detectedBy != "" {
+		data.Name = types.StringValue(base.name)
+		data.DetectedBy = types.StringValue(base.detectedBy)
+		if base.digest != "" {
+			data.Digest = types.StringValue(base.digest)
+		}
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// baseImage is the base image of an image, and how it was found.
+type baseImage struct {
+	name       string
+	digest     string
+	detectedBy string
+}
+
+// detectBaseImage returns the base image of ref from its annotations, or the
+// candidate with the most layers that the layers of ref start with.
+func detectBaseImage(ref name.Reference, candidates []name.Reference, opts ...remote.Option) (baseImage, error) {
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return baseImage{}, err
+	}
+	annotations := map[string]string{}
+	if desc.MediaType.IsIndex() {
+		index, err := v1.ParseIndexManifest(bytes.NewReader(desc.Manifest))
+		if err != nil {
+			return baseImage{}, fmt.Errorf("failed to parse index %s: %w", ref, err)
+		}
+		annotations = index.Annotations
+	}
+	img, err := desc.Image()
+	if err != nil {
+		return baseImage{}, err
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return baseImage{}, err
+	}
+	if manifest.Annotations[baseImageNameAnnotation] != "" {
+		annotations = manifest.Annotations
+	}
+	if annotations[baseImageNameAnnotation] != "" {
+		return baseImage{
+			name:       annotations[baseImageNameAnnotation],
+			digest:     annotations[baseImageDigestAnnotation],
+			detectedBy: "annotation",
+		}, nil
+	}
+
+	var base baseImage
+	matched := 0
+	for _, candidate := range candidates {
+		candidateDesc, err := remote.Get(candidate, opts...)
+		if err != nil {
+			return baseImage{}, err
+		}
+		candidateImg, err := candidateDesc.Image()
+		if err != nil {
+			return baseImage{}, err
+		}
+		candidateManifest, err := candidateImg.Manifest()
+		if err != nil {
+			return baseImage{}, err
+		}
+		layers := candidateManifest.Layers
+		if len(layers) <= matched || !hasLayerPrefix(manifest.Layers, layers) {
+			continue
+		}
+		matched = len(layers)
+		base = baseImage{
+			name:       candidate.String(),
+			digest:     candidateDesc.Digest.String(),
+			detectedBy: "layers",
+		}
+	}
+	return base, nil
+}
+
+// hasLayerPrefix reports whether layers starts with all of prefix.
+func hasLayerPrefix(layers, prefix []v1.Descriptor) bool {
+	if len(prefix) > len(layers) {
+		return false
+	}
+	for i, l := range prefix {
+		if layers[i].Digest != l.Digest {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccBaseImageDataSource(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.SeedRandomImage(t, "base/os:latest")
+	debugDigest := registry.PushImage(t, "base/debug:latest", testImage{Base: registry.Image(t, "base/os:latest")})
+	registry.SeedRandomImage(t, "base/other:latest")
+	registry.PushImage(t, "app/server:latest", testImage{Base: registry.Image(t, "base/debug:latest"), Layers: 2})
+	registry.SeedRandomImage(t, "app/unrelated:latest")
+	registry.PushImage(t, "app/annotated:latest", testImage{
+		Base: registry.Image(t, "base/os:latest"),
+		Annotations: map[string]string{
+			"org.opencontainers.image.base.name":   "docker.io/library/debian:bookworm",
+			"org.opencontainers.image.base.digest": "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		},
+	})
+
+	candidates := `["` + registry.Ref("base/os:latest") + `", "` + registry.Ref("base/debug:latest") + `", "` + registry.Ref("base/other:latest") + `"]`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBaseImageDataSourceConfig(registry.Ref("app/server:latest"), candidates),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_base_image.base",
+						tfjsonpath.New("name"),
+						knownvalue.StringExact(registry.Ref("base/debug:latest")),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_base_image.base",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(debugDigest),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_base_image.base",
+						tfjsonpath.New("detected_by"),
+						knownvalue.StringExact("layers"),
+					),
+				},
+			},
+			{
+				Config: testAccBaseImageDataSourceConfig(registry.Ref("app/annotated:latest"), candidates),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_base_image.base",
+						tfjsonpath.New("name"),
+						knownvalue.StringExact("docker.io/library/debian:bookworm"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_base_image.base",
+						tfjsonpath.New("detected_by"),
+						knownvalue.StringExact("annotation"),
+					),
+				},
+			},
+			{
+				Config: testAccBaseImageDataSourceConfig(registry.Ref("app/unrelated:latest"), candidates),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_base_image.base",
+						tfjsonpath.New("name"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccBaseImageDataSourceConfig(reference, candidates string) string {
+	return `
+data "gcrane_base_image" "base" {
+  reference  = "` + reference + `"
+  candidates = ` + candidates + `
+}
+`
+}
@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GcraneGcrMigrationPlanDataSource{}
+
+func NewGcraneGcrMigrationPlanDataSource() datasource.DataSource {
+	return &GcraneGcrMigrationPlanDataSource{}
+}
+
+// GcraneGcrMigrationPlanDataSource defines the data source implementation.
+type GcraneGcrMigrationPlanDataSource struct {
+	Client *GcraneData
+}
+
+// GcraneGcrMigrationPlanRepositoryModel describes the migration of one
+// Container Registry repository.
+type GcraneGcrMigrationPlanRepositoryModel struct {
+	Destination   types.String `tfsdk:"destination"`
+	TagCount      types.Int64  `tfsdk:"tag_count"`
+	ManifestCount types.Int64  `tfsdk:"manifest_count"`
+	SizeBytes     types.Int64  `tfsdk:"size_bytes"`
+}
+
+// GcraneGcrMigrationPlanDataSourceModel describes the data source data model.
+type GcraneGcrMigrationPlanDataSourceModel struct {
+	Project        types.String `tfsdk:"project"`
+	Hosts          types.List   `tfsdk:"hosts"`
+	Repositories   types.Map    `tfsdk:"repositories"`
+	TotalSizeBytes types.Int64  `tfsdk:"total_size_bytes"`
+}
+
+func (o GcraneGcrMigrationPlanRepositoryModel) AttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"destination":    types.StringType,
+		"tag_count":      types.Int64Type,
+		"manifest_count": types.Int64Type,
+		"size_bytes":     types.Int64Type,
+	}
+}
+
+func (d *GcraneGcrMigrationPlanDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_gcr_migration_plan"
+}
+
+func (d *GcraneGcrMigrationPlanDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Lists every Container Registry repository of a project, with the Artifact Registry repository it migrates to (like the `gcr_to_ar` function), and its tag count and size, for example to stage a migration with `for_each` over `gcrane_copy`",
+		Description:         "Lists every Container Registry repository of a project, with the Artifact Registry repository it migrates to",
+		Attributes: map[string]schema.Attribute{
+			"project": schema.StringAttribute{
+				MarkdownDescription: "Project ID (for example `my-project` or `example.com:my-project`)",
+				Required:            true,
+			},
+			"hosts": schema.ListAttribute{
+				MarkdownDescription: "Container Registry hosts to list (defaults to `gcr.io`, `us.gcr.io`, `eu.gcr.io` and `asia.gcr.io`). Hosts the project has no images on are skipped",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"repositories": schema.MapNestedAttribute{
+				MarkdownDescription: "Repositories with at least one manifest, keyed by Container Registry repository (for example `eu.gcr.io/my-project/my-image`)",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"destination": schema.StringAttribute{
+							MarkdownDescription: "Artifact Registry repository (for example `europe-docker.pkg.dev/my-project/eu.gcr.io/my-image`)",
+							Computed:            true,
+						},
+						"tag_count": schema.Int64Attribute{
+							MarkdownDescription: "Number of tags",
+							Computed:            true,
+						},
+						"manifest_count": schema.Int64Attribute{
+							MarkdownDescription: "Number of manifests, including untagged ones",
+							Computed:            true,
+						},
+						"size_bytes": schema.Int64Attribute{
+							MarkdownDescription: "Sum of the image sizes reported by the registry, in bytes. Layers shared between images are counted for each image",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"total_size_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Sum of `size_bytes` of all repositories",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *GcraneGcrMigrationPlanDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.Client = client
+}
+
+func (d *GcraneGcrMigrationPlanDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GcraneGcrMigrationPlanDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project := data.Project.ValueString()
+	if !projectIdRegexp.MatchString(project) {
+		resp.Diagnostics.AddAttributeError(path.Root("project"), "Invalid project", fmt.Sprintf("Invalid project ID %q.", project))
+		return
+	}
+	hosts := []string{"gcr.io", "us.gcr.io", "eu.gcr.io", "asia.gcr.io"}
+	if !data.Hosts.IsNull() {
+		hosts = nil
+		resp.Diagnostics.Append(data.Hosts.ElementsAs(ctx, &hosts, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	for _, host := range hosts {
+		if _, ok := gcrLocations[strings.ToLower(host)]; !ok {
+			resp.Diagnostics.AddAttributeError(path.Root("hosts"), "Invalid hosts", fmt.Sprintf("%q is not a Container Registry host (gcr.io, us.gcr.io, eu.gcr.io or asia.gcr.io).", host))
+			return
+		}
+	}
+
+	err := d.Client.Setup(ctx, d.Client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return
+	}
+	defer func() {
+		err := d.Client.Cleanup(ctx, d.Client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	repositories := map[string]GcraneGcrMigrationPlanRepositoryModel{}
+	var total int64
+	for _, host := range hosts {
+		// Domain scoped projects use a slash instead of a colon in the path.
+		root, err := name.NewRepository(strings.ToLower(host) + "/" + strings.Replace(project, ":", "/", 1))
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("project"), "Invalid project", err.Error())
+			return
+		}
+
+		repos := []name.Repository{root}
+		for len(repos) > 0 {
+			var repo name.Repository
+			repo, repos = repos[0], repos[1:]
+
+			tags, err := listRepository(ctx, repo, listFilter{IncludeUntagged: true}, d.Client.Keychain, d.Client.Transport)
+			if err != nil && repo == root && isNotFound(err) {
+				break
+			}
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Failed to list repository",
+					fmt.Sprintf("Failed to list repository %s: %s", repo, registryErrorDetail(err, d.Client.Failures)),
+				)
+				return
+			}
+			for _, child := range tags.Children {
+				childRepo, err := name.NewRepository(repo.String() + "/" + child)
+				if err != nil {
+					resp.Diagnostics.AddError("Invalid child repository", err.Error())
+					return
+				}
+				repos = append(repos, childRepo)
+			}
+			if len(tags.Manifests) == 0 || repo == root {
+				continue
+			}
+
+			destination, err := gcrToAr(repo.String())
+			if err != nil {
+				resp.Diagnostics.AddError("Invalid repository", err.Error())
+				return
+			}
+			var size int64
+			for _, manifest := range tags.Manifests {
+				size += int64(manifest.Size)
+			}
+			total += size
+			repositories[repo.String()] = GcraneGcrMigrationPlanRepositoryModel{
+				Destination:   types.StringValue(destination),
+				TagCount:      types.Int64Value(int64(len(tags.Tags))),
+				ManifestCount: types.Int64Value(int64(len(tags.Manifests))),
+				SizeBytes:     types.Int64Value(size),
+			}
+		}
+	}
+
+	repositoriesValue, diags := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: GcraneGcrMigrationPlanRepositoryModel{}.AttributeTypes()}, repositories)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Repositories = repositoriesValue
+	data.TotalSizeBytes = types.Int64Value(total)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccGcrMigrationPlanDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "gcrane_gcr_migration_plan" "plan" {
+  project = "my-project"
+  hosts   = ["europe-docker.pkg.dev"]
+}
+`,
+				ExpectError: regexp.MustCompile(`is not a Container Registry host`),
+			},
+			{
+				Config: `
+data "gcrane_gcr_migration_plan" "plan" {
+  project = "My_Project"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid project`),
+			},
+		},
+	})
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GcraneImageConfigDataSource{}
+
+func NewGcraneImageConfigDataSource() datasource.DataSource {
+	return &GcraneImageConfigDataSource{}
+}
+
+// GcraneImageConfigDataSource defines the data source implementation.
+type GcraneImageConfigDataSource struct {
+	Client *GcraneData
+}
+
+// GcraneImageConfigDataSourceModel describes the data source data model.
+type GcraneImageConfigDataSourceModel struct {
+	Reference    types.String `tfsdk:"reference"`
+	Platform     types.String `tfsdk:"platform"`
+	Digest       types.String `tfsdk:"digest"`
+	Entrypoint   types.List   `tfsdk:"entrypoint"`
+	Cmd          types.List   `tfsdk:"cmd"`
+	Env          types.Map    `tfsdk:"env"`
+	WorkingDir   types.String `tfsdk:"working_dir"`
+	User         types.String `tfsdk:"user"`
+	ExposedPorts types.List   `tfsdk:"exposed_ports"`
+	Ports        types.List   `tfsdk:"ports"`
+}
+
+func (d *GcraneImageConfigDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image_config"
+}
+
+func (d *GcraneImageConfigDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Returns the entrypoint, command, environment and ports an image declares in its config, for example to configure the container of a Cloud Run service from the image",
+		Description:         "Returns the entrypoint, command, environment and ports an image declares in its config",
+		Attributes: map[string]schema.Attribute{
+			"reference": schema.StringAttribute{
+				MarkdownDescription: "Image tag or digest reference",
+				Required:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Image to read the config of (for example `linux/arm64`) when the reference is an image index (defaults to the provider `default_platform`, or `linux/amd64`)",
+				Optional:            true,
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the image the config was read from",
+				Computed:            true,
+			},
+			"entrypoint": schema.ListAttribute{
+				MarkdownDescription: "Entrypoint of the image",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"cmd": schema.ListAttribute{
+				MarkdownDescription: "Default arguments of the entrypoint (`CMD`)",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"env": schema.MapAttribute{
+				MarkdownDescription: "Environment variables of the image",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"working_dir": schema.StringAttribute{
+				MarkdownDescription: "Working directory of the image",
+				Computed:            true,
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "User the image runs as",
+				Computed:            true,
+			},
+			"exposed_ports": schema.ListAttribute{
+				MarkdownDescription: "Exposed ports with their protocol (for example `8080/tcp`), sorted",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"ports": schema.ListAttribute{
+				MarkdownDescription: "Exposed TCP port numbers, sorted",
+				ElementType:         types.Int64Type,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *GcraneImageConfigDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.Client = client
+}
+
+func (d *GcraneImageConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GcraneImageConfigDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ref, err := name.ParseReference(data.Reference.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Invalid reference", err.Error())
+		return
+	}
+	platform := d.Client.DefaultPlatform
+	if !data.Platform.IsNull() {
+		platform, err = v1.ParsePlatform(data.Platform.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("platform"), "Invalid platform", fmt.Sprintf("Unable to parse platform %q: %s", data.Platform.ValueString(), err))
+			return
+		}
+	}
+
+	err = d.Client.Setup(ctx, d.Client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return
+	}
+	defer func() {
+		err := d.Client.Cleanup(ctx, d.Client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	opts := []remote.Option{
+		remote.WithAuthFromKeychain(d.Client.Keychain),
+		remote.WithTransport(d.Client.Transport),
+		remote.WithContext(ctx),
+	}
+	if platform != nil {
+		opts = append(opts, remote.WithPlatform(*platform))
+	}
+	config, digest, err := fetchImageConfig(ref, opts...)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to read image config",
+			fmt.Sprintf("Failed to read the config of %s: %s", ref, registryErrorDetail(err, d.Client.Failures)),
+		)
+		return
+	}
+
+	env := map[string]string{}
+	for _, e := range config.Config.Env {
+		k, v, _ := strings.Cut(e, "=")
+		env[k] = v
+	}
+	exposedPorts := make([]string, 0, len(config.Config.ExposedPorts))
+	ports := []int64{}
+	for port := range config.Config.ExposedPorts {
+		exposedPorts = append(exposedPorts, port)
+		number, protocol, _ := strings.Cut(port, "/")
+		if protocol != "" && protocol != "tcp" {
+			continue
+		}
+		if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+			ports = append(ports, n)
+		}
+	}
+	sort.Strings(exposedPorts)
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	var diags diag.Diagnostics
+	data.Digest = types.StringValue(digest)
+	data.WorkingDir = types.StringValue(config.Config.WorkingDir)
+	data.User = types.StringValue(config.Config.User)
+	data.Entrypoint, diags = types.ListValueFrom(ctx, types.StringType, nonNilStrings(config.Config.Entrypoint))
+	resp.Diagnostics.Append(diags...)
+	data.Cmd, diags = types.ListValueFrom(ctx, types.StringType, nonNilStrings(config.Config.Cmd))
+	resp.Diagnostics.Append(diags...)
+	data.Env, diags = types.MapValueFrom(ctx, types.StringType, env)
+	resp.Diagnostics.Append(diags...)
+	data.ExposedPorts, diags = types.ListValueFrom(ctx, types.StringType, exposedPorts)
+	resp.Diagnostics.Append(diags...)
+	data.Ports, diags = types.ListValueFrom(ctx, types.Int64Type, ports)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// fetchImageConfig returns the config file of ref and the digest of the
+// image, which is the image for the platform in opts for image indexes.
+func fetchImageConfig(ref name.Reference, opts ...remote.Option) (*v1.ConfigFile, string, error) {
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return nil, "", err
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, "", err
+	}
+	config, err := img.ConfigFile()
+	if err != nil {
+		return nil, "", err
+	}
+	return config, digest.String(), nil
+}
+
+// nonNilStrings returns s, or an empty slice when s is nil, so unset lists
+// are empty rather than null.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccImageConfigDataSource(t *testing.T) {
+	registry := newTestRegistry(t)
+	digest := registry.PushImage(t, "source/server:latest", testImage{
+		Config: func(config *v1.Config) {
+			config.Entrypoint = []string{"/server"}
+			config.Cmd = []string{"--listen", ":8080"}
+			config.Env = []string{"MODE=production"}
+			config.ExposedPorts = map[string]struct{}{"8080/tcp": {}, "9090": {}, "5353/udp": {}}
+		},
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "gcrane_image_config" "server" {
+  reference = "` + registry.Ref("source/server:latest") + `"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_config.server",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digest),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_config.server",
+						tfjsonpath.New("entrypoint"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("/server")}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_config.server",
+						tfjsonpath.New("cmd"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.StringExact("--listen"), knownvalue.StringExact(":8080")}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_config.server",
+						tfjsonpath.New("env"),
+						knownvalue.MapExact(map[string]knownvalue.Check{"MODE": knownvalue.StringExact("production")}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_config.server",
+						tfjsonpath.New("ports"),
+						knownvalue.ListExact([]knownvalue.Check{knownvalue.Int64Exact(8080), knownvalue.Int64Exact(9090)}),
+					),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GcraneImageLabelDataSource{}
+
+func NewGcraneImageLabelDataSource() datasource.DataSource {
+	return &GcraneImageLabelDataSource{}
+}
+
+// GcraneImageLabelDataSource defines the data source implementation.
+type GcraneImageLabelDataSource struct {
+	Client *GcraneData
+}
+
+// GcraneImageLabelDataSourceModel describes the data source data model.
+type GcraneImageLabelDataSourceModel struct {
+	Reference types.String `tfsdk:"reference"`
+	Label     types.String `tfsdk:"label"`
+	Platform  types.String `tfsdk:"platform"`
+	Digest    types.String `tfsdk:"digest"`
+	Found     types.Bool   `tfsdk:"found"`
+	Value     types.String `tfsdk:"value"`
+}
+
+func (d *GcraneImageLabelDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image_label"
+}
+
+func (d *GcraneImageLabelDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Returns the value of one label or annotation of an image, for example `org.opencontainers.image.revision`. Annotations of the manifest (or of the image index) are looked up first, and the image config is only fetched when none of them match. Only the value is kept in the state",
+		Description:         "Returns the value of one label or annotation of an image",
+		Attributes: map[string]schema.Attribute{
+			"reference": schema.StringAttribute{
+				MarkdownDescription: "Image tag or digest reference",
+				Required:            true,
+			},
+			"label": schema.StringAttribute{
+				MarkdownDescription: "Name of the label or annotation",
+				Required:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Image to read labels from (for example `linux/arm64`) when the reference is an image index (defaults to the provider `default_platform`, or `linux/amd64`)",
+				Optional:            true,
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the image or image index the value was read from",
+				Computed:            true,
+			},
+			"found": schema.BoolAttribute{
+				MarkdownDescription: "Whether the image has the label or annotation",
+				Computed:            true,
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "Value of the label or annotation, or null when the image does not have it",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *GcraneImageLabelDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.Client = client
+}
+
+func (d *GcraneImageLabelDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GcraneImageLabelDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ref, err := name.ParseReference(data.Reference.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Invalid reference", err.Error())
+		return
+	}
+	platform := d.Client.DefaultPlatform
+	if !data.Platform.IsNull() {
+		platform, err = v1.ParsePlatform(data.Platform.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("platform"), "Invalid platform", fmt.Sprintf("Unable to parse platform %q: %s", data.Platform.ValueString(), err))
+			return
+		}
+	}
+
+	err = d.Client.Setup(ctx, d.Client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return
+	}
+	defer func() {
+		err := d.Client.Cleanup(ctx, d.Client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	opts := []remote.Option{
+		remote.WithAuthFromKeychain(d.Client.Keychain),
+		remote.WithTransport(d.Client.Transport),
+		remote.WithContext(ctx),
+	}
+	if platform != nil {
+		opts = append(opts, remote.WithPlatform(*platform))
+	}
+	label, err := lookupImageLabel(ref, data.Label.ValueString(), opts...)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to read label",
+			fmt.Sprintf("Failed to read label %s of %s: %s", data.Label.ValueString(), ref, registryErrorDetail(err, d.Client.Failures)),
+		)
+		return
+	}
+
+	data.Digest = types.StringValue(label.digest)
+	data.Found = types.BoolValue(label.found)
+	data.Value = types.StringNull()
+	if label.found {
+		data.Value = types.StringValue(label.value)
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// imageLabel is the value of a label or annotation, and the digest of the
+// manifest it was read from.
+type imageLabel struct {
+	digest string
+	found  bool
+	value  string
+}
+
+// lookupImageLabel returns the value of an annotation of ref, or of an image
+// index and then of its image for the platform in opts, falling back to the
+// labels in the image config.
+func lookupImageLabel(ref name.Reference, label string, opts ...remote.Option) (imageLabel, error) {
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return imageLabel{}, err
+	}
+
+	if desc.MediaType.IsIndex() {
+		index, err := desc.ImageIndex()
+		if err != nil {
+			return imageLabel{}, err
+		}
+		manifest, err := index.IndexManifest()
+		if err != nil {
+			return imageLabel{}, err
+		}
+		if value, ok := manifest.Annotations[label]; ok {
+			return imageLabel{digest: desc.Digest.String(), found: true, value: value}, nil
+		}
+	}
+
+	img, err := desc.Image()
+	if err != nil {
+		return imageLabel{}, err
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return imageLabel{}, err
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return imageLabel{}, err
+	}
+	if value, ok := manifest.Annotations[label]; ok {
+		return imageLabel{digest: digest.String(), found: true, value: value}, nil
+	}
+
+	config, err := img.ConfigFile()
+	if err != nil {
+		return imageLabel{}, err
+	}
+	value, ok := config.Config.Labels[label]
+	return imageLabel{digest: digest.String(), found: ok, value: value}, nil
+}
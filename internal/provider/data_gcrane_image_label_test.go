@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccImageLabelDataSource(t *testing.T) {
+	registry := newTestRegistry(t)
+	_, digests := registry.PushIndex(t, "source/multiarch:latest",
+		testImage{Platform: "linux/amd64", Labels: map[string]string{"org.opencontainers.image.revision": "amd64-revision"}},
+		testImage{Platform: "linux/arm64", Labels: map[string]string{"org.opencontainers.image.revision": "arm64-revision"}},
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccImageLabelDataSourceConfig(registry.Ref("source/multiarch:latest"), "org.opencontainers.image.revision"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_label.revision",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digests["linux/arm64"]),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_label.revision",
+						tfjsonpath.New("value"),
+						knownvalue.StringExact("arm64-revision"),
+					),
+				},
+			},
+			{
+				Config: testAccImageLabelDataSourceConfig(registry.Ref("source/multiarch:latest"), "org.opencontainers.image.source"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_label.revision",
+						tfjsonpath.New("found"),
+						knownvalue.Bool(false),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_label.revision",
+						tfjsonpath.New("value"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccImageLabelDataSourceConfig(reference, label string) string {
+	return `
+data "gcrane_image_label" "revision" {
+  reference = "` + reference + `"
+  label     = "` + label + `"
+  platform  = "linux/arm64"
+}
+`
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GcraneImagePullSecretDataSource{}
+
+func NewGcraneImagePullSecretDataSource() datasource.DataSource {
+	return &GcraneImagePullSecretDataSource{}
+}
+
+// GcraneImagePullSecretDataSource defines the data source implementation.
+type GcraneImagePullSecretDataSource struct {
+	Client *GcraneData
+}
+
+// GcraneImagePullSecretDataSourceModel describes the data source data model.
+type GcraneImagePullSecretDataSourceModel struct {
+	Registries       types.List   `tfsdk:"registries"`
+	DockerConfigJSON types.String `tfsdk:"docker_config_json"`
+}
+
+func (d *GcraneImagePullSecretDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image_pull_secret"
+}
+
+func (d *GcraneImagePullSecretDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Renders the `.dockerconfigjson` of a Kubernetes image pull secret with the credentials the provider uses for a set of registries. The credentials are stored in the state; use the `gcrane_docker_config` ephemeral resource to avoid that. Access tokens minted from Google credentials expire after an hour",
+		Description:         "Renders the .dockerconfigjson of a Kubernetes image pull secret with the credentials the provider uses for a set of registries",
+		Attributes: map[string]schema.Attribute{
+			"registries": schema.ListAttribute{
+				MarkdownDescription: "Registries to include (for example `europe-docker.pkg.dev`). Fails if no credentials are found for one of them",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"docker_config_json": schema.StringAttribute{
+				MarkdownDescription: "Docker config file (JSON), for the `.dockerconfigjson` key of a `kubernetes.io/dockerconfigjson` secret",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (d *GcraneImagePullSecretDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.Client = client
+}
+
+func (d *GcraneImagePullSecretDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GcraneImagePullSecretDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(data.Registries.Elements()) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("registries"), "Invalid registries", "At least one registry must be set.")
+		return
+	}
+
+	config, diags := d.Client.DockerConfigFor(ctx, path.Root("registries"), data.Registries)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.DockerConfigJSON = types.StringValue(config)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccImagePullSecretDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccImagePullSecretDataSourceConfig(`["registry.example.com"]`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_pull_secret.secret",
+						tfjsonpath.New("docker_config_json"),
+						knownvalue.StringExact(`{"auths":{"registry.example.com":{"username":"user","password":"password","auth":"dXNlcjpwYXNzd29yZA=="}}}`),
+					),
+				},
+			},
+			{
+				Config:      testAccImagePullSecretDataSourceConfig(`["registry.other.example.com"]`),
+				ExpectError: regexp.MustCompile(`No credentials found for: registry.other.example.com`),
+			},
+		},
+	})
+}
+
+func testAccImagePullSecretDataSourceConfig(registries string) string {
+	return `
+provider "gcrane" {
+  registry_auth {
+    address  = "registry.example.com"
+    username = "user"
+    password = "password"
+  }
+}
+
+data "gcrane_image_pull_secret" "secret" {
+  registries = ` + registries + `
+}
+`
+}
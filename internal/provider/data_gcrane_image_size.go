@@ -0,0 +1,276 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GcraneImageSizeDataSource{}
+
+func NewGcraneImageSizeDataSource() datasource.DataSource {
+	return &GcraneImageSizeDataSource{}
+}
+
+// GcraneImageSizeDataSource defines the data source implementation.
+type GcraneImageSizeDataSource struct {
+	Client *GcraneData
+}
+
+// GcraneImageSizeDataSourcePlatformModel describes the size of one platform
+// of an image index.
+type GcraneImageSizeDataSourcePlatformModel struct {
+	Digest              types.String `tfsdk:"digest"`
+	CompressedSizeBytes types.Int64  `tfsdk:"compressed_size_bytes"`
+	LayerCount          types.Int64  `tfsdk:"layer_count"`
+}
+
+// GcraneImageSizeDataSourceModel describes the data source data model.
+type GcraneImageSizeDataSourceModel struct {
+	Reference           types.String `tfsdk:"reference"`
+	Digest              types.String `tfsdk:"digest"`
+	CompressedSizeBytes types.Int64  `tfsdk:"compressed_size_bytes"`
+	LayerCount          types.Int64  `tfsdk:"layer_count"`
+	Platforms           types.Map    `tfsdk:"platforms"`
+}
+
+func (o GcraneImageSizeDataSourcePlatformModel) AttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"digest":                types.StringType,
+		"compressed_size_bytes": types.Int64Type,
+		"layer_count":           types.Int64Type,
+	}
+}
+
+func (d *GcraneImageSizeDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image_size"
+}
+
+func (d *GcraneImageSizeDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Returns the compressed size of an image, and of each platform of an image index, from the sizes in its manifests. No layers are downloaded",
+		Description:         "Returns the compressed size of an image, and of each platform of an image index, from the sizes in its manifests",
+		Attributes: map[string]schema.Attribute{
+			"reference": schema.StringAttribute{
+				MarkdownDescription: "Image tag or digest reference",
+				Required:            true,
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the image or image index",
+				Computed:            true,
+			},
+			"compressed_size_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Compressed size of the config and layers, in bytes. For image indexes, the size of all platforms, counting layers shared between platforms once. Attestation manifests are not counted",
+				Computed:            true,
+			},
+			"layer_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of layers. For image indexes, the number of distinct layers of all platforms",
+				Computed:            true,
+			},
+			"platforms": schema.MapNestedAttribute{
+				MarkdownDescription: "Size of each image of an image index, by platform (for example `linux/arm64/v8`). Empty for images",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"digest": schema.StringAttribute{
+							MarkdownDescription: "Digest of the image",
+							Computed:            true,
+						},
+						"compressed_size_bytes": schema.Int64Attribute{
+							MarkdownDescription: "Compressed size of the config and layers of the image, in bytes",
+							Computed:            true,
+						},
+						"layer_count": schema.Int64Attribute{
+							MarkdownDescription: "Number of layers of the image",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *GcraneImageSizeDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.Client = client
+}
+
+func (d *GcraneImageSizeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GcraneImageSizeDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ref, err := name.ParseReference(data.Reference.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Invalid reference", err.Error())
+		return
+	}
+
+	err = d.Client.Setup(ctx, d.Client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return
+	}
+	defer func() {
+		err := d.Client.Cleanup(ctx, d.Client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	size, err := fetchImageSize(ref,
+		remote.WithAuthFromKeychain(d.Client.Keychain),
+		remote.WithTransport(d.Client.Transport),
+		remote.WithContext(ctx),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to fetch manifest",
+			fmt.Sprintf("Failed to fetch the manifests of %s: %s", ref, registryErrorDetail(err, d.Client.Failures)),
+		)
+		return
+	}
+
+	platforms := make(map[string]GcraneImageSizeDataSourcePlatformModel, len(size.platforms))
+	for platform, p := range size.platforms {
+		platforms[platform] = GcraneImageSizeDataSourcePlatformModel{
+			Digest:              types.StringValue(p.digest),
+			CompressedSizeBytes: types.Int64Value(p.total()),
+			LayerCount:          types.Int64Value(int64(p.layers)),
+		}
+	}
+	platformsValue, diags := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: GcraneImageSizeDataSourcePlatformModel{}.AttributeTypes()}, platforms)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Digest = types.StringValue(size.digest)
+	data.CompressedSizeBytes = types.Int64Value(size.total())
+	data.LayerCount = types.Int64Value(int64(size.layers))
+	data.Platforms = platformsValue
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// imageSize holds the sizes of the distinct blobs of an image or an image
+// index, and of the images of an index by platform.
+type imageSize struct {
+	digest    string
+	blobs     map[v1.Hash]int64
+	layers    int
+	platforms map[string]*imageSize
+}
+
+// total returns the sum of the blob sizes.
+func (s *imageSize) total() int64 {
+	var total int64
+	for _, size := range s.blobs {
+		total += size
+	}
+	return total
+}
+
+// add adds the blobs of an image manifest.
+func (s *imageSize) add(manifest *v1.Manifest) {
+	s.blobs[manifest.Config.Digest] = manifest.Config.Size
+	for _, l := range manifest.Layers {
+		if _, ok := s.blobs[l.Digest]; !ok {
+			s.layers++
+		}
+		s.blobs[l.Digest] = l.Size
+	}
+}
+
+// fetchImageSize reads the sizes of the config and layers of ref from its
+// manifests, without downloading any blobs.
+func fetchImageSize(ref name.Reference, opts ...remote.Option) (*imageSize, error) {
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	size := &imageSize{digest: desc.Digest.String(), blobs: map[v1.Hash]int64{}, platforms: map[string]*imageSize{}}
+	if !desc.MediaType.IsIndex() {
+		manifest, err := v1.ParseManifest(bytes.NewReader(desc.Manifest))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest %s: %w", ref, err)
+		}
+		size.add(manifest)
+		return size, nil
+	}
+
+	index, err := v1.ParseIndexManifest(bytes.NewReader(desc.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse index %s: %w", ref, err)
+	}
+	for _, m := range index.Manifests {
+		// Skip the attestation manifests buildkit adds to indexes.
+		if !m.MediaType.IsImage() || m.Platform == nil || m.Annotations["vnd.docker.reference.type"] == "attestation-manifest" {
+			continue
+		}
+		child, err := remote.Get(ref.Context().Digest(m.Digest.String()), opts...)
+		if err != nil {
+			return nil, err
+		}
+		manifest, err := v1.ParseManifest(bytes.NewReader(child.Manifest))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse manifest %s: %w", m.Digest, err)
+		}
+		platform := &imageSize{digest: m.Digest.String(), blobs: map[v1.Hash]int64{}}
+		platform.add(manifest)
+		size.add(manifest)
+		size.platforms[m.Platform.String()] = platform
+	}
+	return size, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccImageSizeDataSource(t *testing.T) {
+	registry := newTestRegistry(t)
+	digest, digests := registry.PushIndex(t, "source/multiarch:latest",
+		testImage{Platform: "linux/amd64"},
+		testImage{Platform: "linux/arm64", Layers: 2},
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccImageSizeDataSourceConfig(registry.Ref("source/multiarch:latest")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_size.image",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digest),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_size.image",
+						tfjsonpath.New("layer_count"),
+						knownvalue.Int64Exact(3),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_size.image",
+						tfjsonpath.New("platforms").AtMapKey("linux/arm64").AtMapKey("digest"),
+						knownvalue.StringExact(digests["linux/arm64"]),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_image_size.image",
+						tfjsonpath.New("platforms").AtMapKey("linux/arm64").AtMapKey("layer_count"),
+						knownvalue.Int64Exact(2),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccImageSizeDataSourceConfig(reference string) string {
+	return `
+data "gcrane_image_size" "image" {
+  reference = "` + reference + `"
+}
+`
+}
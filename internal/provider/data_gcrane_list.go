@@ -14,18 +14,29 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
 
-	"github.com/google/go-containerregistry/pkg/gcrane"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
@@ -46,19 +57,51 @@ type GcraneListDataSourceImageModel struct {
 	Created        types.Int64  `tfsdk:"time_created_ms"`
 	Uploaded       types.Int64  `tfsdk:"time_uploaded_ms"`
 	Tags           types.Set    `tfsdk:"tags"`
+	LayerCount     types.Int64  `tfsdk:"layer_count"`
+	TotalSizeBytes types.Int64  `tfsdk:"total_size_bytes"`
+	Annotations    types.Map    `tfsdk:"annotations"`
+}
+
+type GcraneListDataSourceNewestModel struct {
+	Digest   types.String `tfsdk:"digest"`
+	Tag      types.String `tfsdk:"tag"`
+	Uploaded types.Int64  `tfsdk:"time_uploaded_ms"`
 }
 
 type GcraneListDataSourceImagesModel struct {
-	Manifests types.Map `tfsdk:"manifests"`
-	Tags      types.Set `tfsdk:"tags"`
-	Children  types.Set `tfsdk:"children"`
+	Manifests  types.Map  `tfsdk:"manifests"`
+	Digests    types.List `tfsdk:"digests"`
+	Tags       types.Set  `tfsdk:"tags"`
+	Children   types.Set  `tfsdk:"children"`
+	Newest     types.Map  `tfsdk:"newest_per_group"`
+	DigestRefs types.List `tfsdk:"digest_references"`
+	TagRefs    types.List `tfsdk:"tag_references"`
 }
 
 // GcraneListDataSourceModel describes the data source data model.
 type GcraneListDataSourceModel struct {
-	Repository types.String   `tfsdk:"repository"`
-	Id         types.String   `tfsdk:"id"`
-	Images     []types.Object `tfsdk:"images"`
+	Repository      types.String       `tfsdk:"repository"`
+	IncludeUntagged types.Bool         `tfsdk:"include_untagged"`
+	Recursive       types.Bool         `tfsdk:"recursive"`
+	Jobs            types.Int64        `tfsdk:"jobs"`
+	FetchManifests  types.Bool         `tfsdk:"fetch_manifests"`
+	Limit           types.Int64        `tfsdk:"limit"`
+	Offset          types.Int64        `tfsdk:"offset"`
+	SortBy          types.String       `tfsdk:"sort_by"`
+	Descending      types.Bool         `tfsdk:"descending"`
+	GroupBy         types.String       `tfsdk:"group_by"`
+	CreatedAfter    types.String       `tfsdk:"created_after"`
+	CreatedBefore   types.String       `tfsdk:"created_before"`
+	Id              types.String       `tfsdk:"id"`
+	Manifests       types.Map          `tfsdk:"manifests"`
+	Digests         types.List         `tfsdk:"digests"`
+	Tags            types.Set          `tfsdk:"tags"`
+	Children        types.Set          `tfsdk:"children"`
+	Newest          types.Map          `tfsdk:"newest_per_group"`
+	DigestRefs      types.List         `tfsdk:"digest_references"`
+	TagRefs         types.List         `tfsdk:"tag_references"`
+	Repositories    types.Map          `tfsdk:"repositories"`
+	Auth            *AuthOverrideModel `tfsdk:"auth"`
 }
 
 func (o GcraneListDataSourceImageModel) AttributeTypes() map[string]attr.Type {
@@ -70,17 +113,44 @@ func (o GcraneListDataSourceImageModel) AttributeTypes() map[string]attr.Type {
 		"tags": types.SetType{
 			ElemType: types.StringType,
 		},
+		"layer_count":      types.Int64Type,
+		"total_size_bytes": types.Int64Type,
+		"annotations": types.MapType{
+			ElemType: types.StringType,
+		},
+	}
+}
+
+func (o GcraneListDataSourceNewestModel) AttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"digest":           types.StringType,
+		"tag":              types.StringType,
+		"time_uploaded_ms": types.Int64Type,
 	}
 }
 
 func (o GcraneListDataSourceImagesModel) AttributeTypes() map[string]attr.Type {
 	imageModel := GcraneListDataSourceImageModel{}
 	return map[string]attr.Type{
+		"newest_per_group": types.MapType{
+			ElemType: types.ObjectType{
+				AttrTypes: GcraneListDataSourceNewestModel{}.AttributeTypes(),
+			},
+		},
 		"manifests": types.MapType{
 			ElemType: types.ObjectType{
 				AttrTypes: imageModel.AttributeTypes(),
 			},
 		},
+		"digests": types.ListType{
+			ElemType: types.StringType,
+		},
+		"digest_references": types.ListType{
+			ElemType: types.StringType,
+		},
+		"tag_references": types.ListType{
+			ElemType: types.StringType,
+		},
 		"tags": types.SetType{
 			ElemType: types.StringType,
 		},
@@ -104,49 +174,154 @@ func (d *GcraneListDataSource) Schema(ctx context.Context, req datasource.Schema
 				MarkdownDescription: "Repository address",
 				Optional:            true,
 			},
+			"include_untagged": schema.BoolAttribute{
+				MarkdownDescription: "Include manifests that have no tags (defaults to `true`)",
+				Optional:            true,
+			},
 			"id": schema.StringAttribute{
-				MarkdownDescription: "Identifier",
+				MarkdownDescription: "Identifier (hash of the repository and listing options)",
 				Computed:            true,
 			},
-			"images": schema.SetNestedAttribute{
-				MarkdownDescription: "Output of list operation",
+			"recursive": schema.BoolAttribute{
+				MarkdownDescription: "Also list all child repositories recursively",
+				Optional:            true,
+			},
+			"jobs": schema.Int64Attribute{
+				MarkdownDescription: "Number of concurrent requests when listing child repositories or fetching manifests (defaults to the provider `default_jobs`)",
+				Optional:            true,
+			},
+			"fetch_manifests": schema.BoolAttribute{
+				MarkdownDescription: "Fetch the manifest of each returned image to fill `layer_count`, `total_size_bytes` and `annotations`",
+				Optional:            true,
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of manifests to return per repository, in sort order",
+				Optional:            true,
+			},
+			"offset": schema.Int64Attribute{
+				MarkdownDescription: "Number of manifests to skip per repository, in sort order",
+				Optional:            true,
+			},
+			"sort_by": schema.StringAttribute{
+				MarkdownDescription: "Sort manifests by `created`, `uploaded` or `tag` (defaults to `uploaded`)",
+				Optional:            true,
+			},
+			"descending": schema.BoolAttribute{
+				MarkdownDescription: "Sort in descending order (defaults to `true`)",
+				Optional:            true,
+			},
+			"created_after": schema.StringAttribute{
+				MarkdownDescription: "Only include manifests created after this time (RFC 3339 timestamp)",
+				Optional:            true,
+			},
+			"created_before": schema.StringAttribute{
+				MarkdownDescription: "Only include manifests created before this time (RFC 3339 timestamp)",
+				Optional:            true,
+			},
+			"group_by": schema.StringAttribute{
+				MarkdownDescription: "Regular expression matched against tags to fill `newest_per_group`; tags are grouped by the first capture group, or by the whole match if there are no capture groups",
+				Optional:            true,
+			},
+			"repositories": schema.MapNestedAttribute{
+				MarkdownDescription: "Output of list operation for each child repository, keyed by repository (only when `recursive` is set)",
 				Computed:            true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"manifests": schema.MapNestedAttribute{
-							NestedObject: schema.NestedAttributeObject{
-								Attributes: map[string]schema.Attribute{
-									"image_size_bytes": schema.Int64Attribute{
-										Computed: true,
-									},
-									"media_type": schema.StringAttribute{
-										Computed: true,
-									},
-									"time_created_ms": schema.Int64Attribute{
-										Computed: true,
-									},
-									"time_uploaded_ms": schema.Int64Attribute{
-										Computed: true,
-									},
-									"tags": schema.SetAttribute{
-										ElementType: types.StringType,
-										Computed:    true,
-									},
-								},
-							},
-							Computed: true,
-						},
-						"children": schema.SetAttribute{
-							ElementType: types.StringType,
-							Computed:    true,
-						},
-						"tags": schema.SetAttribute{
-							ElementType: types.StringType,
-							Computed:    true,
-						},
+					Attributes: listImagesAttributes(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"auth": schema.SingleNestedBlock{
+				MarkdownDescription: "Credentials for the registry of this listing, overriding the provider credentials, for example to inventory a third-party registry with read-only credentials. Set one of `docker_config`, `username` and `password`, or `access_token`. Data sources cannot take write-only values, so these credentials are stored in the state: to keep them out of it, list with a provider alias configured with the credentials instead, as provider configurations are never stored and accept ephemeral values (like an ephemeral variable, or the `docker_config` of the `gcrane_docker_config` ephemeral resource)",
+				Attributes:          authOverrideDataSourceAttributes(),
+			},
+		},
+	}
+	for k, v := range listImagesAttributes() {
+		resp.Schema.Attributes[k] = v
+	}
+}
+
+func listImagesAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"manifests": schema.MapNestedAttribute{
+			MarkdownDescription: "Manifests keyed by digest",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"image_size_bytes": schema.Int64Attribute{
+						Computed: true,
+					},
+					"media_type": schema.StringAttribute{
+						Computed: true,
+					},
+					"time_created_ms": schema.Int64Attribute{
+						Computed: true,
+					},
+					"time_uploaded_ms": schema.Int64Attribute{
+						Computed: true,
+					},
+					"tags": schema.SetAttribute{
+						ElementType: types.StringType,
+						Computed:    true,
+					},
+					"layer_count": schema.Int64Attribute{
+						MarkdownDescription: "Number of layers (only with `fetch_manifests`)",
+						Computed:            true,
+					},
+					"total_size_bytes": schema.Int64Attribute{
+						MarkdownDescription: "Total size of the config and layers, or of the child manifests for an index (only with `fetch_manifests`). With the provider `default_platform`, indexes are described by their image for that platform",
+						Computed:            true,
+					},
+					"annotations": schema.MapAttribute{
+						MarkdownDescription: "Manifest annotations (only with `fetch_manifests`)",
+						ElementType:         types.StringType,
+						Computed:            true,
+					},
+				},
+			},
+			Computed: true,
+		},
+		"digests": schema.ListAttribute{
+			MarkdownDescription: "Manifest digests in sort order",
+			ElementType:         types.StringType,
+			Computed:            true,
+		},
+		"newest_per_group": schema.MapNestedAttribute{
+			MarkdownDescription: "Most recently uploaded tagged manifest for each tag group matched by `group_by`, keyed by group",
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"digest": schema.StringAttribute{
+						Computed: true,
+					},
+					"tag": schema.StringAttribute{
+						Computed: true,
+					},
+					"time_uploaded_ms": schema.Int64Attribute{
+						Computed: true,
 					},
 				},
 			},
+			Computed: true,
+		},
+		"digest_references": schema.ListAttribute{
+			MarkdownDescription: "Fully qualified `repository@digest` references of the manifests, in sort order",
+			ElementType:         types.StringType,
+			Computed:            true,
+		},
+		"tag_references": schema.ListAttribute{
+			MarkdownDescription: "Fully qualified `repository:tag` references of the tags of the manifests, in sort order",
+			ElementType:         types.StringType,
+			Computed:            true,
+		},
+		"children": schema.SetAttribute{
+			MarkdownDescription: "Child repositories",
+			ElementType:         types.StringType,
+			Computed:            true,
+		},
+		"tags": schema.SetAttribute{
+			MarkdownDescription: "All tags in the repository",
+			ElementType:         types.StringType,
+			Computed:            true,
 		},
 	}
 }
@@ -181,8 +356,11 @@ func (d *GcraneListDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
+	ctx, span := d.Client.Tracer.Start(ctx, "list", attribute.String("gcrane.repository", data.Repository.ValueString()))
+	defer func() { span.End(ctx, resp.Diagnostics) }()
+
 	var err error
-	err = d.Client.Setup(ctx, *d.Client)
+	err = d.Client.Setup(ctx, d.Client)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Could not setup provider",
@@ -191,7 +369,7 @@ func (d *GcraneListDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 	defer func() {
-		err := d.Client.Cleanup(ctx, *d.Client)
+		err := d.Client.Cleanup(ctx, d.Client)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Could not clean up provider",
@@ -200,7 +378,43 @@ func (d *GcraneListDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		}
 	}()
 
-	data.Id = data.Repository
+	if data.Limit.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("limit"), "Invalid limit", "Limit must be zero or greater.")
+	}
+	if !data.Jobs.IsNull() && data.Jobs.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("jobs"), "Invalid jobs", "Jobs must be one or greater.")
+	}
+	if data.Offset.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("offset"), "Invalid offset", "Offset must be zero or greater.")
+	}
+	var groupBy *regexp.Regexp
+	if data.GroupBy.ValueString() != "" {
+		groupBy, err = regexp.Compile(data.GroupBy.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("group_by"), "Invalid group_by", fmt.Sprintf("Failed to parse regular expression: %s", err.Error()))
+		}
+	}
+	var createdAfter, createdBefore time.Time
+	if data.CreatedAfter.ValueString() != "" {
+		createdAfter, err = time.Parse(time.RFC3339, data.CreatedAfter.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("created_after"), "Invalid created_after", fmt.Sprintf("Failed to parse timestamp: %s", err.Error()))
+		}
+	}
+	if data.CreatedBefore.ValueString() != "" {
+		createdBefore, err = time.Parse(time.RFC3339, data.CreatedBefore.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("created_before"), "Invalid created_before", fmt.Sprintf("Failed to parse timestamp: %s", err.Error()))
+		}
+	}
+	switch data.SortBy.ValueString() {
+	case "", "created", "uploaded", "tag":
+	default:
+		resp.Diagnostics.AddAttributeError(path.Root("sort_by"), "Invalid sort_by", fmt.Sprintf("Unknown sort order %q, expected one of: created, uploaded, tag.", data.SortBy.ValueString()))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	repo, err := name.NewRepository(data.Repository.ValueString())
 	if err != nil {
@@ -211,43 +425,359 @@ func (d *GcraneListDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
-	opts := []google.Option{
-		google.WithAuthFromKeychain(gcrane.Keychain),
-		google.WithContext(ctx),
+	keychain := d.Client.Keychain
+	if data.Auth != nil {
+		keychain, err = data.Auth.keychain(d.Client.Keychain, repo.Registry)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("auth"), "Invalid auth", err.Error())
+			return
+		}
+	}
+
+	filter := listFilter{
+		IncludeUntagged: data.IncludeUntagged.IsNull() || data.IncludeUntagged.ValueBool(),
+		Limit:           int(data.Limit.ValueInt64()),
+		Offset:          int(data.Offset.ValueInt64()),
+		SortBy:          data.SortBy.ValueString(),
+		Descending:      data.Descending.IsNull() || data.Descending.ValueBool(),
+		GroupBy:         groupBy,
+		CreatedAfter:    createdAfter,
+		CreatedBefore:   createdBefore,
+	}
+	listCtx, progress := d.Client.Progress(ctx, "list")
+	list := func(ctx context.Context, r name.Repository) (*google.Tags, error) {
+		defer progress.Complete(r.String())
+		// Listings made with other credentials can differ, so they are not
+		// shared.
+		if data.Auth != nil {
+			return listRepository(ctx, r, filter, keychain, d.Client.Transport)
+		}
+		// Listings are filtered while they are read, so only listings made
+		// with the same filter can be shared.
+		return d.Client.CachedList(r.String()+"\n"+filter.key(), func() (*google.Tags, error) {
+			return listRepository(ctx, r, filter, keychain, d.Client.Transport)
+		})
 	}
 
-	tags, err := google.List(repo, opts...)
+	jobs := d.Client.Jobs(data.Jobs)
+
+	var tags *google.Tags
+	childTags := make(map[string]*google.Tags, 0)
+	if data.Recursive.ValueBool() {
+		tags, childTags, err = walkRepositories(listCtx, repo, jobs, list)
+	} else {
+		tags, err = list(listCtx, repo)
+	}
+	progress.Finish()
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Failed to list repository",
-			fmt.Sprintf("Failed to list repository %s: %s", data.Repository.ValueString(), err.Error()),
+			fmt.Sprintf("Failed to list repository %s: %s", data.Repository.ValueString(), registryErrorDetail(err, d.Client.Failures)),
 		)
 		return
 	}
 
-	childList, diags := types.SetValueFrom(ctx, types.StringType, tags.Children)
+	data.Id = types.StringValue(listId(repo, data.Recursive.ValueBool(), filter))
+
+	var details map[string]manifestDetails
+	if data.FetchManifests.ValueBool() {
+		refs := make([]string, 0)
+		for _, digest := range filterManifests(tags.Manifests, filter) {
+			refs = append(refs, fmt.Sprintf("%s@%s", repo, digest))
+		}
+		for k, v := range childTags {
+			for _, digest := range filterManifests(v.Manifests, filter) {
+				refs = append(refs, fmt.Sprintf("%s@%s", k, digest))
+			}
+		}
+		fetchCtx, progress := d.Client.Progress(ctx, "fetch_manifests")
+		details, err = fetchManifestDetails(fetchCtx, refs, jobs, d.Client.DefaultPlatform,
+			remote.WithAuthFromKeychain(keychain),
+			remote.WithTransport(d.Client.Transport),
+			remote.WithContext(ctx),
+		)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Failed to fetch manifests",
+				fmt.Sprintf("Failed to fetch manifests in repository %s: %s", data.Repository.ValueString(), registryErrorDetail(err, d.Client.Failures)),
+			)
+			return
+		}
+		progress.Finish()
+	}
+
+	images, diags := listImagesModel(ctx, repo.String(), tags, filter, details)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	topTagsList, diags := types.SetValueFrom(ctx, types.StringType, tags.Tags)
+	data.Manifests = images.Manifests
+	data.Digests = images.Digests
+	data.Tags = images.Tags
+	data.Children = images.Children
+	data.Newest = images.Newest
+	data.DigestRefs = images.DigestRefs
+	data.TagRefs = images.TagRefs
+
+	repositoriesMap := make(map[string]GcraneListDataSourceImagesModel, len(childTags))
+	for k, v := range childTags {
+		childImages, diags := listImagesModel(ctx, k, v, filter, details)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		repositoriesMap[k] = childImages
+	}
+	data.Repositories, diags = types.MapValueFrom(ctx, types.ObjectType{AttrTypes: GcraneListDataSourceImagesModel{}.AttributeTypes()}, repositoriesMap)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	images := GcraneListDataSourceImagesModel{
-		Children: childList,
-		Tags:     topTagsList,
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Trace(ctx, "read a data source", map[string]interface{}{
+		"repository": data.Repository,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// manifestDetails holds the information read from a manifest itself.
+type manifestDetails struct {
+	LayerCount  int
+	TotalSize   int64
+	Annotations map[string]string
+}
+
+// fetchManifestDetails fetches the manifests of the given digest references,
+// at most jobs at a time, reporting them to the progress reporter of ctx.
+func fetchManifestDetails(ctx context.Context, refs []string, jobs int, platform *v1.Platform, opts ...remote.Option) (map[string]manifestDetails, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	details := make(map[string]manifestDetails, len(refs))
+	sem := make(chan struct{}, jobs)
+	progress := progressFrom(ctx)
+	progress.AddTotal(len(refs))
+
+	for _, ref := range refs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			detail, err := fetchManifestDetail(ref, platform, opts...)
+			progress.Complete(ref)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			details[ref] = detail
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return details, nil
+}
+
+func fetchManifestDetail(ref string, platform *v1.Platform, opts ...remote.Option) (manifestDetails, error) {
+	detail := manifestDetails{}
+
+	digest, err := name.NewDigest(ref)
+	if err != nil {
+		return detail, fmt.Errorf("failed to parse reference %s: %w", ref, err)
+	}
+	desc, err := remote.Get(digest, opts...)
+	if err != nil {
+		return detail, fmt.Errorf("failed to fetch manifest %s: %w", ref, err)
+	}
+
+	if !desc.MediaType.IsIndex() {
+		return imageManifestDetail(ref, desc.Manifest)
+	}
+
+	index, err := v1.ParseIndexManifest(bytes.NewReader(desc.Manifest))
+	if err != nil {
+		return detail, fmt.Errorf("failed to parse index %s: %w", ref, err)
+	}
+	// With a default platform, describe the matching image instead of the
+	// whole index.
+	if platform != nil {
+		for _, m := range index.Manifests {
+			if m.Platform == nil || !m.Platform.Satisfies(*platform) {
+				continue
+			}
+			child, err := remote.Get(digest.Context().Digest(m.Digest.String()), opts...)
+			if err != nil {
+				return detail, fmt.Errorf("failed to fetch manifest %s for platform %s: %w", ref, platform, err)
+			}
+			if child.MediaType.IsImage() {
+				return imageManifestDetail(ref, child.Manifest)
+			}
+		}
+	}
+	for _, m := range index.Manifests {
+		detail.TotalSize += m.Size
+	}
+	detail.Annotations = index.Annotations
+	if detail.Annotations == nil {
+		detail.Annotations = map[string]string{}
+	}
+	return detail, nil
+}
+
+// imageManifestDetail describes an image manifest.
+func imageManifestDetail(ref string, raw []byte) (manifestDetails, error) {
+	detail := manifestDetails{}
+	manifest, err := v1.ParseManifest(bytes.NewReader(raw))
+	if err != nil {
+		return detail, fmt.Errorf("failed to parse manifest %s: %w", ref, err)
+	}
+	detail.LayerCount = len(manifest.Layers)
+	detail.TotalSize = manifest.Config.Size
+	for _, l := range manifest.Layers {
+		detail.TotalSize += l.Size
+	}
+	detail.Annotations = manifest.Annotations
+	if detail.Annotations == nil {
+		detail.Annotations = map[string]string{}
+	}
+	return detail, nil
+}
+
+// listId returns a stable identifier for a listing of repo with the given
+// settings, so differently filtered listings of the same repository differ.
+func listId(repo name.Repository, recursive bool, filter listFilter) string {
+	groupBy := ""
+	if filter.GroupBy != nil {
+		groupBy = filter.GroupBy.String()
+	}
+	key := fmt.Sprintf("%s\nrecursive=%t\n%s\ngroup=%s", repo.String(), recursive, filter.key(), groupBy)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// newestPerGroup groups the tags of the given manifests by the capture group of
+// re and returns the most recently uploaded manifest for each group.
+func newestPerGroup(manifests map[string]google.ManifestInfo, digests []string, re *regexp.Regexp) map[string]GcraneListDataSourceNewestModel {
+	if re == nil {
+		return map[string]GcraneListDataSourceNewestModel{}
+	}
+
+	type candidate struct {
+		digest string
+		tag    string
+		info   google.ManifestInfo
+	}
+	newest := make(map[string]candidate, 0)
+	for _, digest := range digests {
+		info := manifests[digest]
+		for _, tag := range info.Tags {
+			match := re.FindStringSubmatch(tag)
+			if match == nil {
+				continue
+			}
+			group := match[0]
+			if len(match) > 1 {
+				group = match[1]
+			}
+			c, ok := newest[group]
+			if ok {
+				if cmp := info.Uploaded.Compare(c.info.Uploaded); cmp < 0 || (cmp == 0 && tag <= c.tag) {
+					continue
+				}
+			}
+			newest[group] = candidate{digest: digest, tag: tag, info: info}
+		}
+	}
+
+	result := make(map[string]GcraneListDataSourceNewestModel, len(newest))
+	for group, c := range newest {
+		result[group] = GcraneListDataSourceNewestModel{
+			Digest:   types.StringValue(c.digest),
+			Tag:      types.StringValue(c.tag),
+			Uploaded: types.Int64Value(c.info.Uploaded.UnixMilli()),
+		}
+	}
+	return result
+}
+
+// listImagesModel converts a single repository listing into its Terraform model.
+func listImagesModel(ctx context.Context, repo string, tags *google.Tags, filter listFilter, details map[string]manifestDetails) (GcraneListDataSourceImagesModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	images := GcraneListDataSourceImagesModel{}
+
+	childList, d := types.SetValueFrom(ctx, types.StringType, tags.Children)
+	diags.Append(d...)
+	if diags.HasError() {
+		return images, diags
+	}
+	images.Children = childList
+
+	topTagsList, d := types.SetValueFrom(ctx, types.StringType, tags.Tags)
+	diags.Append(d...)
+	if diags.HasError() {
+		return images, diags
+	}
+	images.Tags = topTagsList
+
+	digests := filterManifests(tags.Manifests, filter)
+	digestsList, d := types.ListValueFrom(ctx, types.StringType, digests)
+	diags.Append(d...)
+	if diags.HasError() {
+		return images, diags
+	}
+	images.Digests = digestsList
+
+	digestRefs := make([]string, 0, len(digests))
+	tagRefs := make([]string, 0)
+	for _, k := range digests {
+		digestRefs = append(digestRefs, fmt.Sprintf("%s@%s", repo, k))
+		manifestTags := append([]string{}, tags.Manifests[k].Tags...)
+		sort.Strings(manifestTags)
+		for _, tag := range manifestTags {
+			tagRefs = append(tagRefs, fmt.Sprintf("%s:%s", repo, tag))
+		}
+	}
+	if len(tags.Manifests) == 0 {
+		// Registries other than GCR and Artifact Registry only return tags.
+		topTags := append([]string{}, tags.Tags...)
+		sort.Strings(topTags)
+		for _, tag := range topTags {
+			tagRefs = append(tagRefs, fmt.Sprintf("%s:%s", repo, tag))
+		}
+	}
+	images.DigestRefs, d = types.ListValueFrom(ctx, types.StringType, digestRefs)
+	diags.Append(d...)
+	if diags.HasError() {
+		return images, diags
+	}
+	images.TagRefs, d = types.ListValueFrom(ctx, types.StringType, tagRefs)
+	diags.Append(d...)
+	if diags.HasError() {
+		return images, diags
 	}
 
 	manifestsMap := make(map[string]GcraneListDataSourceImageModel, 0)
-	for k, v := range tags.Manifests {
-		tagsList, diags := types.SetValueFrom(ctx, types.StringType, v.Tags)
-		resp.Diagnostics.Append(diags...)
-		if resp.Diagnostics.HasError() {
-			return
+	for _, k := range digests {
+		v := tags.Manifests[k]
+		tagsList, d := types.SetValueFrom(ctx, types.StringType, v.Tags)
+		diags.Append(d...)
+		if diags.HasError() {
+			return images, diags
 		}
 
 		manifest := GcraneListDataSourceImageModel{
@@ -256,38 +786,31 @@ func (d *GcraneListDataSource) Read(ctx context.Context, req datasource.ReadRequ
 			Created:        types.Int64Value(v.Created.UnixMilli()),
 			Uploaded:       types.Int64Value(v.Uploaded.UnixMilli()),
 			Tags:           tagsList,
+			LayerCount:     types.Int64Null(),
+			TotalSizeBytes: types.Int64Null(),
+			Annotations:    types.MapNull(types.StringType),
+		}
+		if detail, ok := details[fmt.Sprintf("%s@%s", repo, k)]; ok {
+			manifest.LayerCount = types.Int64Value(int64(detail.LayerCount))
+			manifest.TotalSizeBytes = types.Int64Value(detail.TotalSize)
+			manifest.Annotations, d = types.MapValueFrom(ctx, types.StringType, detail.Annotations)
+			diags.Append(d...)
+			if diags.HasError() {
+				return images, diags
+			}
 		}
 		manifestsMap[k] = manifest
 	}
-	manifestMapValue, diags := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: GcraneListDataSourceImageModel{}.AttributeTypes()}, manifestsMap)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+	manifestMapValue, d := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: GcraneListDataSourceImageModel{}.AttributeTypes()}, manifestsMap)
+	diags.Append(d...)
+	if diags.HasError() {
+		return images, diags
 	}
 	images.Manifests = manifestMapValue
 
-	imagesObject, diags := types.ObjectValueFrom(ctx, images.AttributeTypes(), images)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	data.Images = append(data.Images, imagesObject)
+	newestMapValue, d := types.MapValueFrom(ctx, types.ObjectType{AttrTypes: GcraneListDataSourceNewestModel{}.AttributeTypes()}, newestPerGroup(tags.Manifests, digests, filter.GroupBy))
+	diags.Append(d...)
+	images.Newest = newestMapValue
 
-	if len(tags.Manifests) == 0 && len(tags.Children) == 0 {
-		for _, tag := range tags.Tags {
-			tflog.Trace(ctx, fmt.Sprintf("FOO %s:%s\n", repo, tag))
-		}
-	} else {
-		tflog.Trace(ctx, fmt.Sprintf("FOO manifests %v, children: %v: tags: %v\n", tags.Manifests, tags.Children, tags.Tags))
-	}
-
-	// Write logs using the tflog package
-	// Documentation: https://terraform.io/plugin/log
-	tflog.Trace(ctx, "read a data source", map[string]interface{}{
-		"repository": data.Repository,
-	})
-
-	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return images, diags
 }
@@ -14,8 +14,11 @@
 package provider
 
 import (
+	"fmt"
+	"regexp"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/v1/google"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
@@ -23,28 +26,341 @@ import (
 )
 
 func TestAccExampleDataSource(t *testing.T) {
+	registry := newTestListingRegistry(t, 0)
+	manifests := testManifests(2, 0)
+	tagged := manifests[testDigest(1)]
+	tagged.Tags = []string{"latest"}
+	manifests[testDigest(1)] = tagged
+	registry.SetListing("pause", &google.Tags{Tags: []string{"latest"}, Manifests: manifests})
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			// Read testing
 			{
-				Config: testAccExampleDataSourceConfig,
+				Config: testAccExampleDataSourceConfig(registry.Ref("pause")),
 				ConfigStateChecks: []statecheck.StateCheck{
 					statecheck.ExpectKnownValue(
 						"data.gcrane_list.images",
-						tfjsonpath.New("images").AtSliceIndex(0).AtMapKey("tags"),
+						tfjsonpath.New("tags"),
 						knownvalue.SetPartial([]knownvalue.Check{
 							knownvalue.StringExact("latest"),
 						})),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("digests"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(testDigest(1)),
+							knownvalue.StringExact(testDigest(0)),
+						})),
+				},
+			},
+			// The untagged manifest is left out.
+			{
+				Config: testAccExampleDataSourceTaggedConfig(registry.Ref("pause")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("digests"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(testDigest(1)),
+						})),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("manifests"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							testDigest(1): knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"tags": knownvalue.SetExact([]knownvalue.Check{
+									knownvalue.StringExact("latest"),
+								}),
+							}),
+						})),
+				},
+			},
+		},
+	})
+}
+
+func testAccExampleDataSourceConfig(repository string) string {
+	return fmt.Sprintf(`
+data "gcrane_list" "images" {
+  repository = "%s"
+}
+`, repository)
+}
+
+func testAccExampleDataSourceTaggedConfig(repository string) string {
+	return fmt.Sprintf(`
+data "gcrane_list" "images" {
+  repository       = "%s"
+  include_untagged = false
+}
+`, repository)
+}
+
+func TestAccListDataSourceAuth(t *testing.T) {
+	registry := newTestRegistryWithAuth(t, "inventory", "read-only")
+	registry.SeedRandomImage(t, "vendor/image:v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccListDataSourceAuthConfig(registry.Ref("vendor/image"), "wrong"),
+				ExpectError: regexp.MustCompile(`did not accept the credentials`),
+			},
+			{
+				Config: testAccListDataSourceAuthConfig(registry.Ref("vendor/image"), "read-only"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("tags"),
+						knownvalue.SetExact([]knownvalue.Check{
+							knownvalue.StringExact("v1"),
+						})),
 				},
 			},
 		},
 	})
 }
 
-const testAccExampleDataSourceConfig = `
+func testAccListDataSourceAuthConfig(repository string, password string) string {
+	return fmt.Sprintf(`
 data "gcrane_list" "images" {
-  repository = "google/pause"
+  repository = "%s"
+
+  auth {
+    username = "inventory"
+    password = "%s"
+  }
+}
+`, repository, password)
+}
+
+func TestAccListDataSourceFilters(t *testing.T) {
+	registry := newTestListingRegistry(t, 2)
+	manifests := testManifests(5, 4)
+	for i, tag := range []string{"v1.0", "v1.1", "v2.0", "v2.1"} {
+		m := manifests[testDigest(i)]
+		m.Tags = []string{tag}
+		manifests[testDigest(i)] = m
+	}
+	registry.SetListing("project/app", &google.Tags{
+		Tags:      []string{"v1.0", "v1.1", "v2.0", "v2.1"},
+		Manifests: manifests,
+	})
+	repository := registry.Ref("project/app")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The newest manifests first, skipping the untagged one.
+			{
+				Config: testAccListDataSourceFiltersConfig(repository, `
+  limit  = 2
+  offset = 1
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("digests"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(testDigest(3)),
+							knownvalue.StringExact(testDigest(2)),
+						})),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("digest_references"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(repository + "@" + testDigest(3)),
+							knownvalue.StringExact(repository + "@" + testDigest(2)),
+						})),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("tag_references"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(repository + ":v2.1"),
+							knownvalue.StringExact(repository + ":v2.0"),
+						})),
+				},
+			},
+			// The tagged manifests by tag.
+			{
+				Config: testAccListDataSourceFiltersConfig(repository, `
+  include_untagged = false
+  sort_by          = "tag"
+  descending       = false
+  limit            = 3
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("digests"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(testDigest(0)),
+							knownvalue.StringExact(testDigest(1)),
+							knownvalue.StringExact(testDigest(2)),
+						})),
+				},
+			},
+			// The manifests created between the first and the last.
+			{
+				Config: testAccListDataSourceFiltersConfig(repository, `
+  created_after  = "2025-01-01T00:00:30Z"
+  created_before = "2025-01-01T00:03:30Z"
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("digests"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(testDigest(3)),
+							knownvalue.StringExact(testDigest(2)),
+							knownvalue.StringExact(testDigest(1)),
+						})),
+				},
+			},
+			// The newest manifest of each major version.
+			{
+				Config: testAccListDataSourceFiltersConfig(repository, `
+  group_by = "^(v[0-9]+)\\."
+`),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("newest_per_group"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"v1": knownvalue.ObjectExact(map[string]knownvalue.Check{
+								"digest":           knownvalue.StringExact(testDigest(1)),
+								"tag":              knownvalue.StringExact("v1.1"),
+								"time_uploaded_ms": knownvalue.Int64Exact(manifests[testDigest(1)].Uploaded.UnixMilli()),
+							}),
+							"v2": knownvalue.ObjectExact(map[string]knownvalue.Check{
+								"digest":           knownvalue.StringExact(testDigest(3)),
+								"tag":              knownvalue.StringExact("v2.1"),
+								"time_uploaded_ms": knownvalue.Int64Exact(manifests[testDigest(3)].Uploaded.UnixMilli()),
+							}),
+						})),
+				},
+			},
+		},
+	})
+}
+
+func testAccListDataSourceFiltersConfig(repository string, options string) string {
+	return fmt.Sprintf(`
+data "gcrane_list" "images" {
+  repository = "%s"
+%s}
+`, repository, options)
+}
+
+func TestAccListDataSourceRecursive(t *testing.T) {
+	registry := newTestListingRegistry(t, 0)
+	registry.SetListing("project", &google.Tags{
+		Children:  []string{"app", "tools"},
+		Manifests: testManifests(1),
+	})
+	registry.SetListing("project/app", &google.Tags{Manifests: testManifests(3)})
+	registry.SetListing("project/tools", &google.Tags{Manifests: testManifests(2)})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "gcrane_list" "images" {
+  repository = "%s"
+  recursive  = true
+  jobs       = 2
+  limit      = 1
+}
+`, registry.Ref("project")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("children"),
+						knownvalue.SetExact([]knownvalue.Check{
+							knownvalue.StringExact("app"),
+							knownvalue.StringExact("tools"),
+						})),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("digests"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(testDigest(0)),
+						})),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("repositories"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							registry.Ref("project/app"): knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"digests": knownvalue.ListExact([]knownvalue.Check{
+									knownvalue.StringExact(testDigest(2)),
+								}),
+							}),
+							registry.Ref("project/tools"): knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"digests": knownvalue.ListExact([]knownvalue.Check{
+									knownvalue.StringExact(testDigest(1)),
+								}),
+							}),
+						})),
+				},
+			},
+		},
+	})
+}
+
+func TestAccListDataSourceFetchManifests(t *testing.T) {
+	registry := newTestListingRegistry(t, 0)
+	annotated := registry.PushImage(t, "project/images:v1", testImage{
+		Layers:      2,
+		Annotations: map[string]string{"team": "platform"},
+	})
+	plain := registry.PushImage(t, "project/images:v2", testImage{Layers: 3})
+	manifests := testManifests(2)
+	registry.SetListing("project/images", &google.Tags{
+		Tags: []string{"v1", "v2"},
+		Manifests: map[string]google.ManifestInfo{
+			annotated: manifests[testDigest(0)],
+			plain:     manifests[testDigest(1)],
+		},
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "gcrane_list" "images" {
+  repository      = "%s"
+  fetch_manifests = true
+  jobs            = 1
+}
+`, registry.Ref("project/images")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_list.images",
+						tfjsonpath.New("manifests"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							annotated: knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"layer_count": knownvalue.Int64Exact(2),
+								"annotations": knownvalue.MapExact(map[string]knownvalue.Check{
+									"team": knownvalue.StringExact("platform"),
+								}),
+							}),
+							plain: knownvalue.ObjectPartial(map[string]knownvalue.Check{
+								"layer_count": knownvalue.Int64Exact(3),
+								"annotations": knownvalue.MapExact(map[string]knownvalue.Check{}),
+							}),
+						})),
+				},
+			},
+		},
+	})
 }
-`
@@ -0,0 +1,442 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &GcraneProvenanceDataSource{}
+
+func NewGcraneProvenanceDataSource() datasource.DataSource {
+	return &GcraneProvenanceDataSource{}
+}
+
+// GcraneProvenanceDataSource defines the data source implementation.
+type GcraneProvenanceDataSource struct {
+	Client *GcraneData
+}
+
+// GcraneProvenanceDataSourceModel describes the data source data model.
+type GcraneProvenanceDataSourceModel struct {
+	Reference        types.String `tfsdk:"reference"`
+	Platform         types.String `tfsdk:"platform"`
+	Found            types.Bool   `tfsdk:"found"`
+	PredicateType    types.String `tfsdk:"predicate_type"`
+	BuilderId        types.String `tfsdk:"builder_id"`
+	BuildType        types.String `tfsdk:"build_type"`
+	SourceRepository types.String `tfsdk:"source_repository"`
+	SourceRevision   types.String `tfsdk:"source_revision"`
+	InvocationId     types.String `tfsdk:"invocation_id"`
+	Predicate        types.String `tfsdk:"predicate"`
+}
+
+func (d *GcraneProvenanceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_provenance"
+}
+
+func (d *GcraneProvenanceDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Returns the SLSA provenance attestation of an image, from the attestation manifests of its image index (as added by BuildKit) or from its cosign attestations (the `sha256-<digest>.att` tag). SLSA provenance v0.2 and v1 are supported. Signatures of the attestations are not verified",
+		Description:         "Returns the SLSA provenance attestation of an image",
+		Attributes: map[string]schema.Attribute{
+			"reference": schema.StringAttribute{
+				MarkdownDescription: "Image tag or digest reference",
+				Required:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Image to return the provenance of (for example `linux/arm64`) when the reference is an image index (defaults to the provider `default_platform`, or `linux/amd64`)",
+				Optional:            true,
+			},
+			"found": schema.BoolAttribute{
+				MarkdownDescription: "Whether a SLSA provenance attestation was found. The other attributes are null when it was not",
+				Computed:            true,
+			},
+			"predicate_type": schema.StringAttribute{
+				MarkdownDescription: "Predicate type, for example `https://slsa.dev/provenance/v1`",
+				Computed:            true,
+			},
+			"builder_id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the builder",
+				Computed:            true,
+			},
+			"build_type": schema.StringAttribute{
+				MarkdownDescription: "Type of the build",
+				Computed:            true,
+			},
+			"source_repository": schema.StringAttribute{
+				MarkdownDescription: "Source repository the image was built from, when the provenance records it",
+				Computed:            true,
+			},
+			"source_revision": schema.StringAttribute{
+				MarkdownDescription: "Revision (for example the git commit) of the source repository, when the provenance records it",
+				Computed:            true,
+			},
+			"invocation_id": schema.StringAttribute{
+				MarkdownDescription: "Identifier of the build invocation, when the provenance records it",
+				Computed:            true,
+			},
+			"predicate": schema.StringAttribute{
+				MarkdownDescription: "Whole provenance predicate (JSON), for checks on other fields with `jsondecode`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *GcraneProvenanceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.Client = client
+}
+
+func (d *GcraneProvenanceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GcraneProvenanceDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ref, err := name.ParseReference(data.Reference.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("reference"), "Invalid reference", err.Error())
+		return
+	}
+	platform := d.Client.DefaultPlatform
+	if !data.Platform.IsNull() {
+		platform, err = v1.ParsePlatform(data.Platform.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("platform"), "Invalid platform", fmt.Sprintf("Unable to parse platform %q: %s", data.Platform.ValueString(), err))
+			return
+		}
+	}
+	if platform == nil {
+		platform = &v1.Platform{OS: "linux", Architecture: "amd64"}
+	}
+
+	err = d.Client.Setup(ctx, d.Client)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return
+	}
+	defer func() {
+		err := d.Client.Cleanup(ctx, d.Client)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	statement, err := fetchProvenance(ref, *platform,
+		remote.WithAuthFromKeychain(d.Client.Keychain),
+		remote.WithTransport(d.Client.Transport),
+		remote.WithContext(ctx),
+	)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to read provenance",
+			fmt.Sprintf("Failed to read the provenance of %s: %s", ref, registryErrorDetail(err, d.Client.Failures)),
+		)
+		return
+	}
+
+	data.Found = types.BoolValue(statement != nil)
+	data.PredicateType = types.StringNull()
+	data.BuilderId = types.StringNull()
+	data.BuildType = types.StringNull()
+	data.SourceRepository = types.StringNull()
+	data.SourceRevision = types.StringNull()
+	data.InvocationId = types.StringNull()
+	data.Predicate = types.StringNull()
+	if statement != nil {
+		fields := statement.fields()
+		data.PredicateType = types.StringValue(statement.PredicateType)
+		data.BuilderId = stringOrNull(fields.builderId)
+		data.BuildType = stringOrNull(fields.buildType)
+		data.SourceRepository = stringOrNull(fields.sourceRepository)
+		data.SourceRevision = stringOrNull(fields.sourceRevision)
+		data.InvocationId = stringOrNull(fields.invocationId)
+		data.Predicate = types.StringValue(string(statement.Predicate))
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// stringOrNull returns s, or null when s is empty.
+func stringOrNull(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+const (
+	// slsaPredicatePrefix prefixes the predicate types of all SLSA
+	// provenance versions.
+	slsaPredicatePrefix = "https://slsa.dev/provenance/"
+	// dsseMediaType is the media type of the DSSE envelopes of cosign
+	// attestations.
+	dsseMediaType = "application/vnd.dsse.envelope.v1+json"
+)
+
+// inTotoStatement is an in-toto attestation statement.
+type inTotoStatement struct {
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// provenanceFields are the commonly checked fields of a SLSA provenance
+// predicate.
+type provenanceFields struct {
+	builderId        string
+	buildType        string
+	sourceRepository string
+	sourceRevision   string
+	invocationId     string
+}
+
+// slsaDependency is a material (v0.2) or resolved dependency (v1).
+type slsaDependency struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+// revision returns the git commit of a dependency, if any.
+func (d slsaDependency) revision() string {
+	for _, algorithm := range []string{"gitCommit", "sha1"} {
+		if revision := d.Digest[algorithm]; revision != "" {
+			return revision
+		}
+	}
+	return ""
+}
+
+// fields reads the common fields of SLSA provenance v0.2 or v1.
+func (s *inTotoStatement) fields() provenanceFields {
+	var predicate struct {
+		// SLSA provenance v0.2.
+		Builder struct {
+			Id string `json:"id"`
+		} `json:"builder"`
+		BuildType  string `json:"buildType"`
+		Invocation struct {
+			ConfigSource slsaDependency `json:"configSource"`
+		} `json:"invocation"`
+		Metadata struct {
+			BuildInvocationId string `json:"buildInvocationID"`
+		} `json:"metadata"`
+		Materials []slsaDependency `json:"materials"`
+		// SLSA provenance v1.
+		BuildDefinition struct {
+			BuildType          string `json:"buildType"`
+			ExternalParameters struct {
+				Workflow struct {
+					Repository string `json:"repository"`
+				} `json:"workflow"`
+			} `json:"externalParameters"`
+			ResolvedDependencies []slsaDependency `json:"resolvedDependencies"`
+		} `json:"buildDefinition"`
+		RunDetails struct {
+			Builder struct {
+				Id string `json:"id"`
+			} `json:"builder"`
+			Metadata struct {
+				InvocationId string `json:"invocationId"`
+			} `json:"metadata"`
+		} `json:"runDetails"`
+	}
+	// Fields of unexpected types are left empty.
+	_ = json.Unmarshal(s.Predicate, &predicate)
+
+	if strings.HasPrefix(s.PredicateType, slsaPredicatePrefix+"v0.") {
+		fields := provenanceFields{
+			builderId:    predicate.Builder.Id,
+			buildType:    predicate.BuildType,
+			invocationId: predicate.Metadata.BuildInvocationId,
+		}
+		source := predicate.Invocation.ConfigSource
+		if source.URI == "" && len(predicate.Materials) > 0 {
+			source = predicate.Materials[0]
+		}
+		fields.sourceRepository = source.URI
+		fields.sourceRevision = source.revision()
+		return fields
+	}
+
+	fields := provenanceFields{
+		builderId:        predicate.RunDetails.Builder.Id,
+		buildType:        predicate.BuildDefinition.BuildType,
+		invocationId:     predicate.RunDetails.Metadata.InvocationId,
+		sourceRepository: predicate.BuildDefinition.ExternalParameters.Workflow.Repository,
+	}
+	if dependencies := predicate.BuildDefinition.ResolvedDependencies; len(dependencies) > 0 {
+		if fields.sourceRepository == "" {
+			fields.sourceRepository = dependencies[0].URI
+		}
+		fields.sourceRevision = dependencies[0].revision()
+	}
+	return fields
+}
+
+// fetchProvenance returns the SLSA provenance statement of ref, or of its
+// image for platform when ref is an image index. The attestation manifests
+// of the index are looked up first, then the cosign attestations of ref and
+// of the image. It returns nil when there is no provenance.
+func fetchProvenance(ref name.Reference, platform v1.Platform, opts ...remote.Option) (*inTotoStatement, error) {
+	desc, err := remote.Get(ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	repo := ref.Context()
+	digests := []v1.Hash{desc.Digest}
+
+	if desc.MediaType.IsIndex() {
+		index, err := v1.ParseIndexManifest(bytes.NewReader(desc.Manifest))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse index %s: %w", ref, err)
+		}
+		var image *v1.Descriptor
+		for i, m := range index.Manifests {
+			if m.Platform != nil && m.Platform.Satisfies(platform) && m.Annotations["vnd.docker.reference.type"] != "attestation-manifest" {
+				image = &index.Manifests[i]
+				break
+			}
+		}
+		if image == nil {
+			return nil, fmt.Errorf("no image for platform %s in %s", platform, ref)
+		}
+		digests = append(digests, image.Digest)
+
+		for _, m := range index.Manifests {
+			if m.Annotations["vnd.docker.reference.type"] != "attestation-manifest" || m.Annotations["vnd.docker.reference.digest"] != image.Digest.String() {
+				continue
+			}
+			statement, err := readAttestations(repo.Digest(m.Digest.String()), false, opts...)
+			if err != nil || statement != nil {
+				return statement, err
+			}
+		}
+	}
+
+	for _, digest := range digests {
+		tag := repo.Tag(fmt.Sprintf("%s-%s.att", digest.Algorithm, digest.Hex))
+		statement, err := readAttestations(tag, true, opts...)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil || statement != nil {
+			return statement, err
+		}
+	}
+	return nil, nil
+}
+
+// readAttestations returns the first SLSA provenance statement in the layers
+// of the attestation image ref. Cosign attestations are DSSE envelopes
+// wrapping the statement.
+func readAttestations(ref name.Reference, dsse bool, opts ...remote.Option) (*inTotoStatement, error) {
+	img, err := remote.Image(ref, opts...)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range manifest.Layers {
+		// BuildKit and cosign both record the predicate type of each layer.
+		predicateType := l.Annotations["in-toto.io/predicate-type"]
+		if predicateType == "" {
+			predicateType = l.Annotations["predicateType"]
+		}
+		if predicateType != "" && !strings.HasPrefix(predicateType, slsaPredicatePrefix) {
+			continue
+		}
+		if dsse && l.MediaType != dsseMediaType {
+			continue
+		}
+
+		layer, err := img.LayerByDigest(l.Digest)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := layer.Compressed()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if dsse {
+			var envelope struct {
+				Payload string `json:"payload"`
+			}
+			if err := json.Unmarshal(content, &envelope); err != nil {
+				return nil, fmt.Errorf("failed to parse attestation %s: %w", l.Digest, err)
+			}
+			if content, err = base64.StdEncoding.DecodeString(envelope.Payload); err != nil {
+				return nil, fmt.Errorf("failed to decode attestation %s: %w", l.Digest, err)
+			}
+		}
+		statement := &inTotoStatement{}
+		if err := json.Unmarshal(content, statement); err != nil {
+			return nil, fmt.Errorf("failed to parse attestation %s: %w", l.Digest, err)
+		}
+		if strings.HasPrefix(statement.PredicateType, slsaPredicatePrefix) {
+			return statement, nil
+		}
+	}
+	return nil, nil
+}
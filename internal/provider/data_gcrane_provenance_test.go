@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+const testProvenanceStatement = `{
+  "_type": "https://in-toto.io/Statement/v1",
+  "predicateType": "https://slsa.dev/provenance/v1",
+  "predicate": {
+    "buildDefinition": {
+      "buildType": "https://actions.github.io/buildtypes/workflow/v1",
+      "externalParameters": {"workflow": {"repository": "https://github.com/example/app"}},
+      "resolvedDependencies": [{"uri": "git+https://github.com/example/app@refs/heads/main", "digest": {"gitCommit": "0123456789abcdef0123456789abcdef01234567"}}]
+    },
+    "runDetails": {
+      "builder": {"id": "https://github.com/actions/runner/github-hosted"},
+      "metadata": {"invocationId": "https://github.com/example/app/actions/runs/1/attempts/1"}
+    }
+  }
+}`
+
+func TestAccProvenanceDataSource(t *testing.T) {
+	registry := newTestRegistry(t)
+	digest := registry.SeedRandomImage(t, "app/server:latest")
+	registry.SeedRandomImage(t, "app/unattested:latest")
+
+	// Attach the provenance like cosign attest does.
+	envelope := `{"payloadType":"application/vnd.in-toto+json","payload":"` + base64.StdEncoding.EncodeToString([]byte(testProvenanceStatement)) + `","signatures":[]}`
+	attestation, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       static.NewLayer([]byte(envelope), dsseMediaType),
+		Annotations: map[string]string{"predicateType": "https://slsa.dev/provenance/v1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := name.NewTag(registry.Ref("app/server:" + strings.Replace(digest, ":", "-", 1) + ".att"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(tag, attestation); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProvenanceDataSourceConfig(registry.Ref("app/server:latest")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_provenance.server",
+						tfjsonpath.New("builder_id"),
+						knownvalue.StringExact("https://github.com/actions/runner/github-hosted"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_provenance.server",
+						tfjsonpath.New("source_repository"),
+						knownvalue.StringExact("https://github.com/example/app"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.gcrane_provenance.server",
+						tfjsonpath.New("source_revision"),
+						knownvalue.StringExact("0123456789abcdef0123456789abcdef01234567"),
+					),
+				},
+			},
+			{
+				Config: testAccProvenanceDataSourceConfig(registry.Ref("app/unattested:latest")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.gcrane_provenance.server",
+						tfjsonpath.New("found"),
+						knownvalue.Bool(false),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccProvenanceDataSourceConfig(reference string) string {
+	return `
+data "gcrane_provenance" "server" {
+  reference = "` + reference + `"
+}
+`
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// destinationPolicy restricts the destinations that may be written to.
+type destinationPolicy struct {
+	allowed []destinationPattern
+	denied  []destinationPattern
+}
+
+// destinationPattern is a compiled glob pattern.
+type destinationPattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+// newDestinationPolicy compiles the allowed and denied glob patterns.
+func newDestinationPolicy(allowed, denied []string) (destinationPolicy, error) {
+	var err error
+	policy := destinationPolicy{}
+	if policy.allowed, err = compileGlobs(allowed); err != nil {
+		return policy, err
+	}
+	if policy.denied, err = compileGlobs(denied); err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+func compileGlobs(globs []string) ([]destinationPattern, error) {
+	patterns := make([]destinationPattern, 0, len(globs))
+	for _, glob := range globs {
+		re, err := globRegexp(normalizeGlob(glob))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", glob, err)
+		}
+		patterns = append(patterns, destinationPattern{glob: glob, re: re})
+	}
+	return patterns, nil
+}
+
+// globRegexp converts a glob to a regular expression. "*" matches within a
+// path segment, "**" matches across segments and "?" matches one character.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// normalizeGlob writes the registry of a glob like the registries of parsed
+// references: without a scheme, docker.io as index.docker.io, and Docker Hub
+// for globs without a registry. Registries with wildcards are kept as is.
+func normalizeGlob(glob string) string {
+	glob = strings.TrimPrefix(glob, "https://")
+	glob = strings.TrimPrefix(glob, "http://")
+	registry, rest, ok := strings.Cut(glob, "/")
+	if !ok || strings.ContainsAny(registry, "*?") {
+		return glob
+	}
+	if !strings.ContainsAny(registry, ".:") && registry != "localhost" {
+		return name.DefaultRegistry + "/" + glob
+	}
+	key, err := registryKey(registry)
+	if err != nil {
+		return glob
+	}
+	return key + "/" + rest
+}
+
+// match returns the first pattern matching the destination, either with or
+// without its tag or digest.
+func match(patterns []destinationPattern, ref name.Reference) (string, bool) {
+	for _, p := range patterns {
+		if p.re.MatchString(ref.Name()) || p.re.MatchString(ref.Context().Name()) {
+			return p.glob, true
+		}
+	}
+	return "", false
+}
+
+// Check returns an error when destination is denied, or when allowed
+// patterns are configured and none of them match. Destinations are matched
+// fully qualified, so that myorg/app matches index.docker.io/myorg/app.
+func (p destinationPolicy) Check(destination string) error {
+	if len(p.denied) == 0 && len(p.allowed) == 0 {
+		return nil
+	}
+	ref, err := name.ParseReference(destination)
+	if err != nil {
+		return fmt.Errorf("invalid destination %s: %w", destination, err)
+	}
+	if glob, ok := match(p.denied, ref); ok {
+		return fmt.Errorf("destination %s matches denied_destinations pattern %q", destination, glob)
+	}
+	if len(p.allowed) > 0 {
+		if _, ok := match(p.allowed, ref); !ok {
+			return fmt.Errorf("destination %s does not match any allowed_destinations pattern", destination)
+		}
+	}
+	return nil
+}
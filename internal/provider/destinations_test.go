@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDestinationPolicy(t *testing.T) {
+	policy, err := newDestinationPolicy(
+		[]string{"europe-docker.pkg.dev/prod/**", "gcr.io/staging/app?"},
+		[]string{"europe-docker.pkg.dev/prod/secret/*"},
+	)
+	if err != nil {
+		t.Fatalf("newDestinationPolicy() failed: %v", err)
+	}
+
+	tests := []struct {
+		destination string
+		// wantErr is part of the expected error, empty when allowed.
+		wantErr string
+	}{
+		{destination: "europe-docker.pkg.dev/prod/images/app:v1"},
+		{destination: "europe-docker.pkg.dev/prod/images/app@sha256:" + strings.Repeat("0", 64)},
+		{destination: "gcr.io/staging/app1:latest"},
+		{destination: "europe-docker.pkg.dev/prod/secret/app:v1", wantErr: `denied_destinations pattern "europe-docker.pkg.dev/prod/secret/*"`},
+		// "**" matches across path segments, "*" does not.
+		{destination: "europe-docker.pkg.dev/prod/secret/app/nested:v1"},
+		// "?" matches a single character.
+		{destination: "gcr.io/staging/app12:latest", wantErr: "does not match any allowed_destinations pattern"},
+		{destination: "europe-docker.pkg.dev/dev/images/app:v1", wantErr: "does not match any allowed_destinations pattern"},
+		// Patterns match whole references, not prefixes.
+		{destination: "europe-docker.pkg.dev.example.com/prod/app:v1", wantErr: "does not match any allowed_destinations pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.destination, func(t *testing.T) {
+			err := policy.Check(tt.destination)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check() failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Check() error = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDestinationPolicyEmpty(t *testing.T) {
+	policy, err := newDestinationPolicy(nil, nil)
+	if err != nil {
+		t.Fatalf("newDestinationPolicy() failed: %v", err)
+	}
+	if err := policy.Check("docker.io/library/busybox:latest"); err != nil {
+		t.Errorf("Check() failed without patterns: %v", err)
+	}
+}
+
+func TestDestinationPolicyNormalized(t *testing.T) {
+	policy, err := newDestinationPolicy(nil, []string{"index.docker.io/myorg/**", "docker.io/other/*", "https://registry.example.com/prod/**"})
+	if err != nil {
+		t.Fatalf("newDestinationPolicy() failed: %v", err)
+	}
+
+	// Every way of writing a denied destination is denied.
+	denied := []string{
+		"myorg/app:v1",
+		"docker.io/myorg/app",
+		"index.docker.io/myorg/team/app@sha256:" + strings.Repeat("0", 64),
+		"other/app",
+		"registry.example.com/prod/app:v1",
+	}
+	for _, destination := range denied {
+		if err := policy.Check(destination); err == nil || !strings.Contains(err.Error(), "denied_destinations") {
+			t.Errorf("Check(%q) error = %v, want it denied", destination, err)
+		}
+	}
+	if err := policy.Check("library/busybox:latest"); err != nil {
+		t.Errorf("Check() failed: %v", err)
+	}
+	if err := policy.Check("registry.example.com/prod/App:v1"); err == nil || !strings.Contains(err.Error(), "invalid destination") {
+		t.Errorf("Check() of an invalid destination error = %v, want it rejected", err)
+	}
+}
+
+func TestNormalizeGlob(t *testing.T) {
+	tests := map[string]string{
+		"gcr.io/project/**":               "gcr.io/project/**",
+		"https://gcr.io/project/*":        "gcr.io/project/*",
+		"docker.io/myorg/**":              "index.docker.io/myorg/**",
+		"myorg/**":                        "index.docker.io/myorg/**",
+		"localhost:5000/project/**":       "localhost:5000/project/**",
+		"*.pkg.dev/prod/**":               "*.pkg.dev/prod/**",
+		"**":                              "**",
+		"europe-docker.pkg.dev/prod/app?": "europe-docker.pkg.dev/prod/app?",
+	}
+	for glob, want := range tests {
+		if got := normalizeGlob(glob); got != want {
+			t.Errorf("normalizeGlob(%q) = %q, want %q", glob, got, want)
+		}
+	}
+}
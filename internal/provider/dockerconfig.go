@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// dockerConfigAuth is the entry of a registry in a Docker config file.
+type dockerConfigAuth struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+	RegistryToken string `json:"registrytoken,omitempty"`
+}
+
+// dockerConfigFile is a Docker config file, as used for Kubernetes
+// dockerconfigjson secrets.
+type dockerConfigFile struct {
+	Auths map[string]dockerConfigAuth `json:"auths"`
+}
+
+// renderDockerConfig resolves credentials for registries from keychain and
+// renders them as a Docker config file. Registries without credentials are
+// left out and returned in missing.
+func renderDockerConfig(ctx context.Context, keychain authn.Keychain, registries []string) (config string, missing []string, err error) {
+	file := dockerConfigFile{Auths: map[string]dockerConfigAuth{}}
+	for _, address := range registries {
+		key, err := registryKey(address)
+		if err != nil {
+			return "", nil, err
+		}
+		reg, err := name.NewRegistry(key)
+		if err != nil {
+			return "", nil, err
+		}
+		auth, err := authn.Resolve(ctx, keychain, reg)
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to resolve credentials for %s: %w", key, err)
+		}
+		if auth == authn.Anonymous {
+			missing = append(missing, key)
+			continue
+		}
+		cfg, err := authn.Authorization(ctx, auth)
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to get credentials for %s: %w", key, err)
+		}
+		entry := dockerConfigAuth{
+			Username:      cfg.Username,
+			Password:      cfg.Password,
+			Auth:          cfg.Auth,
+			IdentityToken: cfg.IdentityToken,
+			RegistryToken: cfg.RegistryToken,
+		}
+		if entry.Auth == "" && (entry.Username != "" || entry.Password != "") {
+			entry.Auth = base64.StdEncoding.EncodeToString([]byte(entry.Username + ":" + entry.Password))
+		}
+		if key == name.DefaultRegistry {
+			// Docker expects the legacy index address for Docker Hub.
+			key = "https://index.docker.io/v1/"
+		}
+		file.Auths[key] = entry
+	}
+
+	contents, err := json.Marshal(file)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(contents), missing, nil
+}
+
+// DockerConfigFor renders a Docker config file for registries (the list
+// attribute at p), or for the registries configured in the provider when the
+// list is null.
+func (g *GcraneData) DockerConfigFor(ctx context.Context, p path.Path, list types.List) (config string, diags diag.Diagnostics) {
+	registries := g.Registries
+	explicit := !list.IsNull()
+	if explicit {
+		registries = nil
+		diags.Append(list.ElementsAs(ctx, &registries, false)...)
+		if diags.HasError() {
+			return "", diags
+		}
+	}
+
+	err := g.Setup(ctx, g)
+	if err != nil {
+		diags.AddError("Could not setup provider", err.Error())
+		return "", diags
+	}
+	defer func() {
+		err := g.Cleanup(ctx, g)
+		if err != nil {
+			diags.AddError("Could not clean up provider", err.Error())
+		}
+	}()
+
+	config, missing, err := renderDockerConfig(ctx, g.Keychain, registries)
+	if err != nil {
+		diags.AddAttributeError(p, "Unable to render Docker config", err.Error())
+		return "", diags
+	}
+	if explicit && len(missing) > 0 {
+		diags.AddAttributeError(p, "Missing registry credentials", "No credentials found for: "+strings.Join(missing, ", "))
+		return "", diags
+	}
+	return config, diags
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &AuthTokenEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &AuthTokenEphemeralResource{}
+
+func NewAuthTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &AuthTokenEphemeralResource{}
+}
+
+// AuthTokenEphemeralResource defines the ephemeral resource implementation.
+type AuthTokenEphemeralResource struct {
+	Client *GcraneData
+}
+
+// AuthTokenEphemeralResourceModel describes the ephemeral resource data model.
+type AuthTokenEphemeralResourceModel struct {
+	Scopes      types.List   `tfsdk:"scopes"`
+	Username    types.String `tfsdk:"username"`
+	AccessToken types.String `tfsdk:"access_token"`
+	ExpiresAt   types.String `tfsdk:"expires_at"`
+}
+
+// accessTokenUsername is the username registries expect with Google OAuth
+// access tokens (for example for docker login).
+const accessTokenUsername = "oauth2accesstoken"
+
+func (r *AuthTokenEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_auth_token"
+}
+
+func (r *AuthTokenEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Mints a short-lived access token for `gcr.io` and `pkg.dev` registries from the Google credentials configured in the provider (or the application default credentials). The token is never stored in the state, and can be passed to other providers, for example as an image pull secret.",
+		Description:         "Mints a short-lived access token for gcr.io and pkg.dev registries from the Google credentials configured in the provider",
+		Attributes: map[string]schema.Attribute{
+			"scopes": schema.ListAttribute{
+				MarkdownDescription: "OAuth scopes of the token (defaults to `https://www.googleapis.com/auth/cloud-platform`). Ignored when the provider is configured with an `access_token`",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "Username to use with the token when logging in to a registry (`oauth2accesstoken`)",
+				Computed:            true,
+			},
+			"access_token": schema.StringAttribute{
+				MarkdownDescription: "Access token",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "Expiry time of the token (RFC 3339), if known",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *AuthTokenEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = client
+}
+
+func (r *AuthTokenEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data AuthTokenEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	scopes := []string{cloudPlatformScope}
+	if !data.Scopes.IsNull() {
+		scopes = nil
+		resp.Diagnostics.Append(data.Scopes.ElementsAs(ctx, &scopes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if len(scopes) == 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("scopes"), "Invalid scopes", "At least one scope must be set.")
+			return
+		}
+	}
+
+	source := googleTokenSource(defaultTokenSource)
+	if r.Client != nil && r.Client.GoogleTokenSource != nil {
+		source = r.Client.GoogleTokenSource
+	}
+	ts, err := source(ctx, scopes)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to find Google credentials", err.Error())
+		return
+	}
+	token, err := ts.Token()
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to mint access token", err.Error())
+		return
+	}
+
+	data.Username = types.StringValue(accessTokenUsername)
+	data.AccessToken = types.StringValue(token.AccessToken)
+	data.ExpiresAt = types.StringNull()
+	if !token.Expiry.IsZero() {
+		data.ExpiresAt = types.StringValue(token.Expiry.UTC().Format(time.RFC3339))
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
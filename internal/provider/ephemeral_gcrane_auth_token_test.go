@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+// testAccProtoV6ProviderFactoriesWithEcho also serves the echo provider, which
+// exposes ephemeral values in the state for checks.
+var testAccProtoV6ProviderFactoriesWithEcho = map[string]func() (tfprotov6.ProviderServer, error){
+	"gcrane": providerserver.NewProtocol6WithError(New("test")()),
+	"echo":   echoprovider.NewProviderServer(),
+}
+
+func TestAccAuthTokenEphemeralResource(t *testing.T) {
+	if os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") == "" {
+		t.Skip("GOOGLE_APPLICATION_CREDENTIALS must be set for auth token tests")
+	}
+
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAuthTokenEphemeralResourceConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"echo.token",
+						tfjsonpath.New("data").AtMapKey("username"),
+						knownvalue.StringExact("oauth2accesstoken"),
+					),
+					statecheck.ExpectKnownValue(
+						"echo.token",
+						tfjsonpath.New("data").AtMapKey("has_token"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+			{
+				Config:      testAccAuthTokenEphemeralResourceEmptyScopesConfig,
+				ExpectError: regexp.MustCompile(`At least one scope must be set`),
+			},
+		},
+	})
+}
+
+const testAccAuthTokenEphemeralResourceConfig = `
+ephemeral "gcrane_auth_token" "token" {}
+
+provider "echo" {
+  data = {
+    username  = ephemeral.gcrane_auth_token.token.username
+    has_token = ephemeral.gcrane_auth_token.token.access_token != ""
+  }
+}
+
+resource "echo" "token" {}
+`
+
+const testAccAuthTokenEphemeralResourceEmptyScopesConfig = `
+ephemeral "gcrane_auth_token" "token" {
+  scopes = []
+}
+
+provider "echo" {
+  data = ephemeral.gcrane_auth_token.token.username
+}
+
+resource "echo" "token" {}
+`
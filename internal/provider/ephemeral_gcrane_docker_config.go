@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ ephemeral.EphemeralResource = &DockerConfigEphemeralResource{}
+var _ ephemeral.EphemeralResourceWithConfigure = &DockerConfigEphemeralResource{}
+
+func NewDockerConfigEphemeralResource() ephemeral.EphemeralResource {
+	return &DockerConfigEphemeralResource{}
+}
+
+// DockerConfigEphemeralResource defines the ephemeral resource implementation.
+type DockerConfigEphemeralResource struct {
+	Client *GcraneData
+}
+
+// DockerConfigEphemeralResourceModel describes the ephemeral resource data model.
+type DockerConfigEphemeralResourceModel struct {
+	Registries   types.List   `tfsdk:"registries"`
+	DockerConfig types.String `tfsdk:"docker_config"`
+}
+
+func (r *DockerConfigEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_docker_config"
+}
+
+func (r *DockerConfigEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Renders a Docker config file (the `.dockerconfigjson` of Kubernetes image pull secrets) with the credentials the provider uses for a set of registries. The credentials are never stored in the state.",
+		Description:         "Renders a Docker config file with the credentials the provider uses for a set of registries",
+		Attributes: map[string]schema.Attribute{
+			"registries": schema.ListAttribute{
+				MarkdownDescription: "Registries to include (for example `europe-docker.pkg.dev`). Fails if no credentials are found for one of them. Defaults to the registries configured with `registry_auth`, `github_token` and `credential_helpers` in the provider",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"docker_config": schema.StringAttribute{
+				MarkdownDescription: "Docker config file (JSON)",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *DockerConfigEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = client
+}
+
+func (r *DockerConfigEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data DockerConfigEphemeralResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config, diags := r.Client.DockerConfigFor(ctx, path.Root("registries"), data.Registries)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.DockerConfig = types.StringValue(config)
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccDockerConfigEphemeralResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactoriesWithEcho,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDockerConfigEphemeralResourceConfig(""),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"echo.config",
+						tfjsonpath.New("data").AtMapKey("auths").AtMapKey("registry.example.com").AtMapKey("auth"),
+						knownvalue.StringExact("dXNlcjpwYXNzd29yZA=="),
+					),
+				},
+			},
+			{
+				Config:      testAccDockerConfigEphemeralResourceConfig(`registries = ["registry.other.example.com"]`),
+				ExpectError: regexp.MustCompile(`No credentials found for: registry.other.example.com`),
+			},
+		},
+	})
+}
+
+func testAccDockerConfigEphemeralResourceConfig(registries string) string {
+	return `
+provider "gcrane" {
+  registry_auth {
+    address  = "registry.example.com"
+    username = "user"
+    password = "password"
+  }
+}
+
+ephemeral "gcrane_docker_config" "config" {
+  ` + registries + `
+}
+
+provider "echo" {
+  data = jsondecode(ephemeral.gcrane_docker_config.config.docker_config)
+}
+
+resource "echo" "config" {}
+`
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ArtifactRegistryRefFunction{}
+
+var (
+	// projectIdRegexp matches project IDs, optionally scoped to a domain
+	// (like example.com:my-project).
+	projectIdRegexp = regexp.MustCompile(`^([a-z0-9.-]+:)?[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+	// locationRegexp matches regions (europe-west4) and multi-regions (us).
+	locationRegexp = regexp.MustCompile(`^[a-z]+(-[a-z]+[0-9]+)?$`)
+	// arRepositoryRegexp matches Artifact Registry repository IDs.
+	arRepositoryRegexp = regexp.MustCompile(`^[a-z]([a-z0-9-]{0,61}[a-z0-9])?$`)
+)
+
+func NewArtifactRegistryRefFunction() function.Function {
+	return &ArtifactRegistryRefFunction{}
+}
+
+// ArtifactRegistryRefFunction defines the function implementation.
+type ArtifactRegistryRefFunction struct{}
+
+func (f *ArtifactRegistryRefFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "artifact_registry_ref"
+}
+
+func (f *ArtifactRegistryRefFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Builds an Artifact Registry image reference",
+		MarkdownDescription: "Builds a fully qualified Artifact Registry reference (`LOCATION-docker.pkg.dev/PROJECT/REPOSITORY/IMAGE`) with a tag or digest, validating each part.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "project",
+				MarkdownDescription: "Project ID (for example `my-project` or `example.com:my-project`)",
+			},
+			function.StringParameter{
+				Name:                "location",
+				MarkdownDescription: "Region or multi-region of the repository (for example `europe-west4` or `us`)",
+			},
+			function.StringParameter{
+				Name:                "repository",
+				MarkdownDescription: "Artifact Registry repository ID",
+			},
+			function.StringParameter{
+				Name:                "image",
+				MarkdownDescription: "Image name, which may contain slashes",
+			},
+			function.StringParameter{
+				Name:                "tag_or_digest",
+				MarkdownDescription: "Tag, or digest starting with `sha256:`. When null, the reference has neither",
+				AllowNullValue:      true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *ArtifactRegistryRefFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var project, location, repository, image string
+	var tagOrDigest types.String
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &project, &location, &repository, &image, &tagOrDigest))
+	if resp.Error != nil {
+		return
+	}
+
+	if !projectIdRegexp.MatchString(project) {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid project ID %q", project))
+		return
+	}
+	if !locationRegexp.MatchString(location) {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid location %q", location))
+		return
+	}
+	if !arRepositoryRegexp.MatchString(repository) {
+		resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("invalid repository ID %q", repository))
+		return
+	}
+
+	// Domain scoped projects use a slash instead of a colon in the path.
+	reference := fmt.Sprintf("%s-docker.pkg.dev/%s/%s/%s", location, strings.Replace(project, ":", "/", 1), repository, image)
+	if _, err := name.NewRepository(reference); err != nil {
+		resp.Error = function.NewArgumentFuncError(3, fmt.Sprintf("invalid image %q: %s", image, err))
+		return
+	}
+
+	if !tagOrDigest.IsNull() {
+		if strings.Contains(tagOrDigest.ValueString(), ":") {
+			if _, err := v1.NewHash(tagOrDigest.ValueString()); err != nil {
+				resp.Error = function.NewArgumentFuncError(4, fmt.Sprintf("invalid digest %q: %s", tagOrDigest.ValueString(), err))
+				return
+			}
+			reference += "@" + tagOrDigest.ValueString()
+		} else {
+			if _, err := name.NewTag(reference + ":" + tagOrDigest.ValueString()); err != nil {
+				resp.Error = function.NewArgumentFuncError(4, fmt.Sprintf("invalid tag %q: %s", tagOrDigest.ValueString(), err))
+				return
+			}
+			reference += ":" + tagOrDigest.ValueString()
+		}
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, reference))
+}
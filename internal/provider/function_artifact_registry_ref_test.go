@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccArtifactRegistryRefFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccArtifactRegistryRefFunctionConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("tagged", knownvalue.StringExact("europe-west4-docker.pkg.dev/my-project/my-repo/team/my-image:v1.2.3")),
+					statecheck.ExpectKnownOutputValue("domain_scoped", knownvalue.StringExact("us-docker.pkg.dev/example.com/my-project/my-repo/my-image")),
+				},
+			},
+			{
+				Config:      testAccArtifactRegistryRefFunctionInvalidConfig,
+				ExpectError: regexp.MustCompile(`invalid location`),
+			},
+		},
+	})
+}
+
+const testAccArtifactRegistryRefFunctionConfig = `
+output "tagged" {
+  value = provider::gcrane::artifact_registry_ref("my-project", "europe-west4", "my-repo", "team/my-image", "v1.2.3")
+}
+
+output "domain_scoped" {
+  value = provider::gcrane::artifact_registry_ref("example.com:my-project", "us", "my-repo", "my-image", null)
+}
+`
+
+const testAccArtifactRegistryRefFunctionInvalidConfig = `
+output "invalid" {
+  value = provider::gcrane::artifact_registry_ref("my-project", "Europe West", "my-repo", "my-image", null)
+}
+`
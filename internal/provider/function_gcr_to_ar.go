@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &GcrToArFunction{}
+
+// gcrLocations maps Container Registry hosts to the location of the
+// Artifact Registry repositories they are redirected to.
+var gcrLocations = map[string]string{
+	"gcr.io":      "us",
+	"us.gcr.io":   "us",
+	"eu.gcr.io":   "europe",
+	"asia.gcr.io": "asia",
+}
+
+func NewGcrToArFunction() function.Function {
+	return &GcrToArFunction{}
+}
+
+// GcrToArFunction defines the function implementation.
+type GcrToArFunction struct{}
+
+func (f *GcrToArFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "gcr_to_ar"
+}
+
+func (f *GcrToArFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Translates a Container Registry reference to Artifact Registry",
+		MarkdownDescription: "Translates a `gcr.io`, `us.gcr.io`, `eu.gcr.io` or `asia.gcr.io` reference to the Artifact Registry repository that Container Registry traffic is redirected to, for example `eu.gcr.io/my-project/my-image:v1` to `europe-docker.pkg.dev/my-project/eu.gcr.io/my-image:v1`. The tag or digest is kept.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "reference",
+				MarkdownDescription: "Container Registry image reference",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// gcrToAr translates a Container Registry reference to Artifact Registry.
+func gcrToAr(reference string) (string, error) {
+	if _, err := name.ParseReference(reference, name.WeakValidation); err != nil {
+		return "", fmt.Errorf("invalid reference %q: %w", reference, err)
+	}
+
+	host, path, _ := strings.Cut(reference, "/")
+	location, ok := gcrLocations[strings.ToLower(host)]
+	if !ok {
+		return "", fmt.Errorf("%q is not a Container Registry reference (gcr.io, us.gcr.io, eu.gcr.io or asia.gcr.io)", reference)
+	}
+
+	// Domain scoped projects (example.com/my-project) span two segments.
+	segments := strings.SplitN(path, "/", 3)
+	projectSegments := 1
+	if strings.Contains(segments[0], ".") {
+		projectSegments = 2
+	}
+	if len(segments) <= projectSegments {
+		return "", fmt.Errorf("reference %q has no image name after the project", reference)
+	}
+	project := strings.Join(segments[:projectSegments], "/")
+	image := strings.Join(segments[projectSegments:], "/")
+
+	return fmt.Sprintf("%s-docker.pkg.dev/%s/%s/%s", location, project, strings.ToLower(host), image), nil
+}
+
+func (f *GcrToArFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var reference string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &reference))
+	if resp.Error != nil {
+		return
+	}
+
+	translated, err := gcrToAr(reference)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, translated))
+}
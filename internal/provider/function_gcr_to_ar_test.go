@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccGcrToArFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGcrToArFunctionConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("gcr", knownvalue.StringExact("us-docker.pkg.dev/my-project/gcr.io/my-image")),
+					statecheck.ExpectKnownOutputValue("eu", knownvalue.StringExact("europe-docker.pkg.dev/my-project/eu.gcr.io/team/my-image:v1")),
+				},
+			},
+			{
+				Config:      testAccGcrToArFunctionInvalidConfig,
+				ExpectError: regexp.MustCompile(`not a Container Registry reference`),
+			},
+		},
+	})
+}
+
+const testAccGcrToArFunctionConfig = `
+output "gcr" {
+  value = provider::gcrane::gcr_to_ar("gcr.io/my-project/my-image")
+}
+
+output "eu" {
+  value = provider::gcrane::gcr_to_ar("eu.gcr.io/my-project/team/my-image:v1")
+}
+`
+
+const testAccGcrToArFunctionInvalidConfig = `
+output "invalid" {
+  value = provider::gcrane::gcr_to_ar("ghcr.io/owner/image")
+}
+`
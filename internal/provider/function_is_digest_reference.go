@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &IsDigestReferenceFunction{}
+
+func NewIsDigestReferenceFunction() function.Function {
+	return &IsDigestReferenceFunction{}
+}
+
+// IsDigestReferenceFunction defines the function implementation.
+type IsDigestReferenceFunction struct{}
+
+func (f *IsDigestReferenceFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "is_digest_reference"
+}
+
+func (f *IsDigestReferenceFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Checks whether an image reference is pinned to a digest",
+		MarkdownDescription: "Returns `true` when the reference is a valid image reference pinned to a digest (like `my-image@sha256:...` or `my-image:v1@sha256:...`), and `false` otherwise, including for invalid references. Useful in `validation` blocks to require immutable references.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "reference",
+				MarkdownDescription: "Image reference",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *IsDigestReferenceFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var reference string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &reference))
+	if resp.Error != nil {
+		return
+	}
+
+	parts, err := parseReference(reference)
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, err == nil && parts.Digest != ""))
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccIsDigestReferenceFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccIsDigestReferenceFunctionConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("digest", knownvalue.Bool(true)),
+					statecheck.ExpectKnownOutputValue("tag", knownvalue.Bool(false)),
+					statecheck.ExpectKnownOutputValue("invalid", knownvalue.Bool(false)),
+				},
+			},
+		},
+	})
+}
+
+const testAccIsDigestReferenceFunctionConfig = `
+output "digest" {
+  value = provider::gcrane::is_digest_reference("ghcr.io/owner/image@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+}
+
+output "tag" {
+  value = provider::gcrane::is_digest_reference("ghcr.io/owner/image:v1")
+}
+
+output "invalid" {
+  value = provider::gcrane::is_digest_reference("ghcr.io/owner/image@sha256:1234")
+}
+`
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &JoinReferenceFunction{}
+
+func NewJoinReferenceFunction() function.Function {
+	return &JoinReferenceFunction{}
+}
+
+// JoinReferenceFunction defines the function implementation.
+type JoinReferenceFunction struct{}
+
+func (f *JoinReferenceFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "join_reference"
+}
+
+func (f *JoinReferenceFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Builds an image reference from its components",
+		MarkdownDescription: "Builds an image reference from a registry, repository, tag and digest, validating each of them. This is the inverse of `parse_reference`. The registry, tag and digest may be null to leave them out.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "registry",
+				MarkdownDescription: "Registry host (for example `ghcr.io` or `localhost:5000`), or null for Docker Hub",
+				AllowNullValue:      true,
+			},
+			function.StringParameter{
+				Name:                "repository",
+				MarkdownDescription: "Repository within the registry (for example `my-project/my-repo/my-image`)",
+			},
+			function.StringParameter{
+				Name:                "tag",
+				MarkdownDescription: "Tag, or null",
+				AllowNullValue:      true,
+			},
+			function.StringParameter{
+				Name:                "digest",
+				MarkdownDescription: "Digest (for example `sha256:...`), or null",
+				AllowNullValue:      true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *JoinReferenceFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var registry, tag, digest types.String
+	var repository string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &registry, &repository, &tag, &digest))
+	if resp.Error != nil {
+		return
+	}
+
+	reference := repository
+	if registry.ValueString() != "" {
+		if _, err := name.NewRegistry(registry.ValueString()); err != nil {
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid registry %q: %s", registry.ValueString(), err))
+			return
+		}
+		reference = registry.ValueString() + "/" + repository
+	}
+	if _, err := name.NewRepository(reference); err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid repository %q: %s", repository, err))
+		return
+	}
+	if tag.ValueString() != "" {
+		if _, err := name.NewTag(reference + ":" + tag.ValueString()); err != nil {
+			resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("invalid tag %q: %s", tag.ValueString(), err))
+			return
+		}
+		reference += ":" + tag.ValueString()
+	}
+	if digest.ValueString() != "" {
+		if _, err := v1.NewHash(digest.ValueString()); err != nil {
+			resp.Error = function.NewArgumentFuncError(3, fmt.Sprintf("invalid digest %q: %s", digest.ValueString(), err))
+			return
+		}
+		reference += "@" + digest.ValueString()
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, reference))
+}
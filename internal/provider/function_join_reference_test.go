@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccJoinReferenceFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccJoinReferenceFunctionConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("tagged", knownvalue.StringExact("ghcr.io/owner/image:v1")),
+					statecheck.ExpectKnownOutputValue("round_trip", knownvalue.StringExact("localhost:5000/team/image:v2@sha256:0000000000000000000000000000000000000000000000000000000000000000")),
+				},
+			},
+			{
+				Config:      testAccJoinReferenceFunctionInvalidConfig,
+				ExpectError: regexp.MustCompile(`invalid tag`),
+			},
+		},
+	})
+}
+
+const testAccJoinReferenceFunctionConfig = `
+output "tagged" {
+  value = provider::gcrane::join_reference("ghcr.io", "owner/image", "v1", null)
+}
+
+locals {
+  parts = provider::gcrane::parse_reference("localhost:5000/team/image:v2@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+}
+
+output "round_trip" {
+  value = provider::gcrane::join_reference(local.parts.registry, local.parts.repository, local.parts.tag, local.parts.digest)
+}
+`
+
+const testAccJoinReferenceFunctionInvalidConfig = `
+output "invalid" {
+  value = provider::gcrane::join_reference("ghcr.io", "owner/image", "not/a/tag", null)
+}
+`
@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &NormalizeReferenceFunction{}
+
+func NewNormalizeReferenceFunction() function.Function {
+	return &NormalizeReferenceFunction{}
+}
+
+// NormalizeReferenceFunction defines the function implementation.
+type NormalizeReferenceFunction struct{}
+
+func (f *NormalizeReferenceFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_reference"
+}
+
+func (f *NormalizeReferenceFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Normalizes an image reference",
+		MarkdownDescription: "Normalizes an image reference the way Docker does, so references from user input and from registries can be compared: the registry host is lower cased, `docker.io` is added when there is no registry, `library/` is added for official images and `latest` is added when there is neither a tag nor a digest. For example `nginx` becomes `docker.io/library/nginx:latest`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "reference",
+				MarkdownDescription: "Image reference",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *NormalizeReferenceFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var reference string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &reference))
+	if resp.Error != nil {
+		return
+	}
+
+	normalized, err := normalizeReference(reference)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, normalized))
+}
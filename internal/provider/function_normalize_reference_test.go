@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccNormalizeReferenceFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNormalizeReferenceFunctionConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("official", knownvalue.StringExact("docker.io/library/nginx:latest")),
+					statecheck.ExpectKnownOutputValue("host", knownvalue.StringExact("ghcr.io/owner/image:v1")),
+				},
+			},
+			{
+				Config:      testAccNormalizeReferenceFunctionInvalidConfig,
+				ExpectError: regexp.MustCompile(`invalid reference`),
+			},
+		},
+	})
+}
+
+const testAccNormalizeReferenceFunctionConfig = `
+output "official" {
+  value = provider::gcrane::normalize_reference("nginx")
+}
+
+output "host" {
+  value = provider::gcrane::normalize_reference("GHCR.io/owner/image:v1")
+}
+`
+
+const testAccNormalizeReferenceFunctionInvalidConfig = `
+output "invalid" {
+  value = provider::gcrane::normalize_reference("Owner/Image")
+}
+`
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &ParseReferenceFunction{}
+
+func NewParseReferenceFunction() function.Function {
+	return &ParseReferenceFunction{}
+}
+
+// ParseReferenceFunction defines the function implementation.
+type ParseReferenceFunction struct{}
+
+// referencePartsAttributeTypes are the attributes of a parsed reference.
+var referencePartsAttributeTypes = map[string]attr.Type{
+	"registry":   types.StringType,
+	"repository": types.StringType,
+	"tag":        types.StringType,
+	"digest":     types.StringType,
+}
+
+// ParseReferenceModel describes a parsed reference.
+type ParseReferenceModel struct {
+	Registry   types.String `tfsdk:"registry"`
+	Repository types.String `tfsdk:"repository"`
+	Tag        types.String `tfsdk:"tag"`
+	Digest     types.String `tfsdk:"digest"`
+}
+
+func (f *ParseReferenceFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_reference"
+}
+
+func (f *ParseReferenceFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Parses an image reference into its components",
+		MarkdownDescription: "Parses an image reference into `registry`, `repository`, `tag` and `digest`, without contacting any registry. References are completed like Docker does: `docker.io` is used when there is no registry, `library/` is added for official images and `latest` is used when there is neither a tag nor a digest. `tag` is null for references with only a digest and `digest` is null for references without one.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "reference",
+				MarkdownDescription: "Image reference (for example `nginx:1.27` or `europe-docker.pkg.dev/my-project/my-repo/my-image@sha256:...`)",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: referencePartsAttributeTypes,
+		},
+	}
+}
+
+func (f *ParseReferenceFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var reference string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &reference))
+	if resp.Error != nil {
+		return
+	}
+
+	parts, err := parseReference(reference)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result := ParseReferenceModel{
+		Registry:   types.StringValue(parts.Registry),
+		Repository: types.StringValue(parts.Repository),
+		Tag:        types.StringNull(),
+		Digest:     types.StringNull(),
+	}
+	if parts.Tag != "" {
+		result.Tag = types.StringValue(parts.Tag)
+	}
+	if parts.Digest != "" {
+		result.Digest = types.StringValue(parts.Digest)
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, &result))
+}
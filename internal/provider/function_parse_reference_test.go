@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccParseReferenceFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccParseReferenceFunctionConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("docker_hub", knownvalue.ObjectExact(map[string]knownvalue.Check{
+						"registry":   knownvalue.StringExact("docker.io"),
+						"repository": knownvalue.StringExact("library/nginx"),
+						"tag":        knownvalue.StringExact("latest"),
+						"digest":     knownvalue.Null(),
+					})),
+					statecheck.ExpectKnownOutputValue("pinned", knownvalue.ObjectExact(map[string]knownvalue.Check{
+						"registry":   knownvalue.StringExact("europe-docker.pkg.dev"),
+						"repository": knownvalue.StringExact("my-project/my-repo/my-image"),
+						"tag":        knownvalue.Null(),
+						"digest":     knownvalue.StringExact("sha256:0000000000000000000000000000000000000000000000000000000000000000"),
+					})),
+				},
+			},
+			{
+				Config:      testAccParseReferenceFunctionInvalidConfig,
+				ExpectError: regexp.MustCompile(`invalid reference`),
+			},
+		},
+	})
+}
+
+const testAccParseReferenceFunctionConfig = `
+output "docker_hub" {
+  value = provider::gcrane::parse_reference("nginx")
+}
+
+output "pinned" {
+  value = provider::gcrane::parse_reference("europe-docker.pkg.dev/my-project/my-repo/my-image@sha256:0000000000000000000000000000000000000000000000000000000000000000")
+}
+`
+
+const testAccParseReferenceFunctionInvalidConfig = `
+output "invalid" {
+  value = provider::gcrane::parse_reference("Not A Reference")
+}
+`
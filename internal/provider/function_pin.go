@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &PinFunction{}
+
+func NewPinFunction() function.Function {
+	return &PinFunction{}
+}
+
+// PinFunction defines the function implementation.
+type PinFunction struct{}
+
+func (f *PinFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "pin"
+}
+
+func (f *PinFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Builds a digest pinned image reference",
+		MarkdownDescription: "Builds an immutable image reference from a repository, an optional tag and a digest, validating all of them. Returns `repository@digest`, or `repository:tag@digest` when a tag is given (the tag is then only informational, the digest is what gets pulled).",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "repository",
+				MarkdownDescription: "Repository, without a tag or digest (for example `europe-docker.pkg.dev/my-project/my-repo/my-image`)",
+			},
+			function.StringParameter{
+				Name:                "tag",
+				MarkdownDescription: "Tag to keep in the reference, or null",
+				AllowNullValue:      true,
+			},
+			function.StringParameter{
+				Name:                "digest",
+				MarkdownDescription: "Digest of the image (for example `sha256:...`)",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *PinFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var repository, digest string
+	var tag types.String
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &repository, &tag, &digest))
+	if resp.Error != nil {
+		return
+	}
+
+	if _, err := name.NewRepository(repository); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid repository %q: %s", repository, err))
+		return
+	}
+	pinned := repository
+	if !tag.IsNull() {
+		if _, err := name.NewTag(repository + ":" + tag.ValueString()); err != nil {
+			resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid tag %q: %s", tag.ValueString(), err))
+			return
+		}
+		pinned += ":" + tag.ValueString()
+	}
+	if _, err := v1.NewHash(digest); err != nil {
+		resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("invalid digest %q: %s", digest, err))
+		return
+	}
+	pinned += "@" + digest
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, pinned))
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccPinFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccPinFunctionConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("pinned", knownvalue.StringExact("ghcr.io/owner/image@sha256:0000000000000000000000000000000000000000000000000000000000000000")),
+					statecheck.ExpectKnownOutputValue("pinned_tag", knownvalue.StringExact("ghcr.io/owner/image:v1@sha256:0000000000000000000000000000000000000000000000000000000000000000")),
+				},
+			},
+			{
+				Config:      testAccPinFunctionInvalidConfig,
+				ExpectError: regexp.MustCompile(`invalid digest`),
+			},
+		},
+	})
+}
+
+const testAccPinFunctionConfig = `
+output "pinned" {
+  value = provider::gcrane::pin("ghcr.io/owner/image", null, "sha256:0000000000000000000000000000000000000000000000000000000000000000")
+}
+
+output "pinned_tag" {
+  value = provider::gcrane::pin("ghcr.io/owner/image", "v1", "sha256:0000000000000000000000000000000000000000000000000000000000000000")
+}
+`
+
+const testAccPinFunctionInvalidConfig = `
+output "invalid" {
+  value = provider::gcrane::pin("ghcr.io/owner/image", null, "sha256:1234")
+}
+`
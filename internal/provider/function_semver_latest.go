@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &SemverLatestFunction{}
+
+// semverTagRegexp matches tags with a full semantic version, so tags like
+// "1" or "20240101" are not mistaken for versions.
+var semverTagRegexp = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+func NewSemverLatestFunction() function.Function {
+	return &SemverLatestFunction{}
+}
+
+// SemverLatestFunction defines the function implementation.
+type SemverLatestFunction struct{}
+
+func (f *SemverLatestFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "semver_latest"
+}
+
+func (f *SemverLatestFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Returns the highest semantic version tag",
+		MarkdownDescription: "Returns the tag with the highest semantic version (`1.2.3` or `v1.2.3`) from a list of tags, optionally matching a version constraint in the Terraform syntax (for example `~> 1.2` or `>= 1.0, < 2.0`). Tags that are not semantic versions are ignored, as are pre-releases unless the constraint names one. Returns null when no tag matches.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "tags",
+				MarkdownDescription: "Tags to choose from, for example the `tags` of the `gcrane_list` data source",
+				ElementType:         types.StringType,
+			},
+			function.StringParameter{
+				Name:                "constraint",
+				MarkdownDescription: "Version constraint, or null to allow any version",
+				AllowNullValue:      true,
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// semverLatest returns the tag with the highest version allowed by
+// constraints, or an empty string if there is none.
+func semverLatest(tags []string, constraints version.Constraints) string {
+	var latest *version.Version
+	latestTag := ""
+	for _, tag := range tags {
+		if !semverTagRegexp.MatchString(tag) {
+			continue
+		}
+		v, err := version.NewSemver(tag)
+		if err != nil {
+			continue
+		}
+		if constraints != nil {
+			if !constraints.Check(v) {
+				continue
+			}
+		} else if v.Prerelease() != "" {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+			latestTag = tag
+		}
+	}
+	return latestTag
+}
+
+func (f *SemverLatestFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var tags []string
+	var constraint types.String
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &tags, &constraint))
+	if resp.Error != nil {
+		return
+	}
+
+	var constraints version.Constraints
+	if !constraint.IsNull() {
+		var err error
+		constraints, err = version.NewConstraint(constraint.ValueString())
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid version constraint %q: %s", constraint.ValueString(), err))
+			return
+		}
+	}
+
+	result := types.StringNull()
+	if latest := semverLatest(tags, constraints); latest != "" {
+		result = types.StringValue(latest)
+	}
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, result))
+}
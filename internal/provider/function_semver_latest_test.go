@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+)
+
+func TestAccSemverLatestFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSemverLatestFunctionConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("latest", knownvalue.StringExact("1.10.0")),
+					statecheck.ExpectKnownOutputValue("constrained", knownvalue.StringExact("v1.9.9")),
+					statecheck.ExpectKnownOutputValue("none", knownvalue.Null()),
+				},
+			},
+			{
+				Config:      testAccSemverLatestFunctionInvalidConfig,
+				ExpectError: regexp.MustCompile(`invalid version constraint`),
+			},
+		},
+	})
+}
+
+const testAccSemverLatestFunctionConfig = `
+locals {
+  tags = ["latest", "20240101", "v1.9.9", "1.10.0", "2.0.0-rc1"]
+}
+
+output "latest" {
+  value = provider::gcrane::semver_latest(local.tags, null)
+}
+
+output "constrained" {
+  value = provider::gcrane::semver_latest(local.tags, "~> 1.9.0")
+}
+
+output "none" {
+  value = provider::gcrane::semver_latest(local.tags, ">= 3.0.0")
+}
+`
+
+const testAccSemverLatestFunctionInvalidConfig = `
+output "invalid" {
+  value = provider::gcrane::semver_latest(["1.0.0"], "not a constraint")
+}
+`
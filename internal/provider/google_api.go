@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+)
+
+// artifactRegistryEndpoint is the base URL of the Artifact Registry API.
+var artifactRegistryEndpoint = "https://artifactregistry.googleapis.com/v1"
+
+// googleAPIClient returns an HTTP client for Google APIs, authenticated with
+// the configured Google credentials and sent through the registry transport,
+// so proxies, CA certificates and retries apply. Quota is billed to the
+// billing project, when set.
+func (c *GcraneData) googleAPIClient(ctx context.Context) (*http.Client, error) {
+	source := googleTokenSource(defaultTokenSource)
+	if c.GoogleTokenSource != nil {
+		source = c.GoogleTokenSource
+	}
+	ts, err := source(ctx, []string{cloudPlatformScope})
+	if err != nil {
+		return nil, fmt.Errorf("unable to find Google credentials: %w", err)
+	}
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if c.BillingProject != "" {
+		base = headerTransport{inner: base, headers: map[string]string{"X-Goog-User-Project": c.BillingProject}}
+	}
+	return &http.Client{Transport: &oauth2.Transport{Source: ts, Base: base}}, nil
+}
+
+// googleAPIError is the error body of Google APIs.
+type googleAPIError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// getGoogleAPI fetches a Google API URL into out.
+func getGoogleAPI(ctx context.Context, client *http.Client, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr googleAPIError
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("GET %s: %d %s: %s", u, resp.StatusCode, apiErr.Error.Status, apiErr.Error.Message)
+		}
+		return fmt.Errorf("GET %s: unexpected status code %d", u, resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("GET %s: unable to parse response: %w", u, err)
+	}
+	return nil
+}
+
+// artifactRegistryRepository is an Artifact Registry repository.
+type artifactRegistryRepository struct {
+	Name        string            `json:"name"`
+	Format      string            `json:"format"`
+	Mode        string            `json:"mode"`
+	Description string            `json:"description"`
+	Labels      map[string]string `json:"labels"`
+	SizeBytes   int64             `json:"sizeBytes,string"`
+	CreateTime  string            `json:"createTime"`
+	UpdateTime  string            `json:"updateTime"`
+}
+
+// listArtifactRegistryRepositories lists the repositories of a project in a
+// location, following pagination.
+func listArtifactRegistryRepositories(ctx context.Context, client *http.Client, project, location string) ([]artifactRegistryRepository, error) {
+	var repositories []artifactRegistryRepository
+	pageToken := ""
+	for {
+		query := url.Values{"pageSize": {"1000"}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		u := fmt.Sprintf("%s/projects/%s/locations/%s/repositories?%s", artifactRegistryEndpoint, url.PathEscape(project), url.PathEscape(location), query.Encode())
+
+		var page struct {
+			Repositories  []artifactRegistryRepository `json:"repositories"`
+			NextPageToken string                       `json:"nextPageToken"`
+		}
+		if err := getGoogleAPI(ctx, client, u, &page); err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, page.Repositories...)
+		if page.NextPageToken == "" {
+			return repositories, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
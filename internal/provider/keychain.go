@@ -0,0 +1,223 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	ecr "github.com/awslabs/amazon-ecr-credential-helper/ecr-login"
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/docker/docker-credential-helpers/credentials"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"golang.org/x/oauth2"
+	googauth "golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/google/externalaccount"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// ecrKeychain resolves credentials for Amazon ECR registries
+// (*.dkr.ecr.*.amazonaws.com) from the standard AWS SDK credential chain.
+var ecrKeychain = authn.NewKeychainFromHelper(ecr.NewECRHelper(ecr.WithLogger(io.Discard)))
+
+// staticKeychain resolves credentials from a fixed set of registries.
+type staticKeychain map[string]authn.AuthConfig
+
+var _ authn.Keychain = staticKeychain{}
+
+// registryKey normalizes a registry address (which may include a scheme or
+// a path, like Docker allows) into the form used by name.Registry.
+func registryKey(address string) (string, error) {
+	address = strings.TrimPrefix(address, "https://")
+	address = strings.TrimPrefix(address, "http://")
+	address, _, _ = strings.Cut(address, "/")
+	reg, err := name.NewRegistry(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid registry address %s: %w", address, err)
+	}
+	return reg.RegistryStr(), nil
+}
+
+func (k staticKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	cfg, ok := k[target.RegistryStr()]
+	if !ok {
+		return authn.Anonymous, nil
+	}
+	return authn.FromConfig(cfg), nil
+}
+
+// googleKeychain resolves a fixed authenticator for Google registries
+// (gcr.io, pkg.dev) only.
+type googleKeychain struct {
+	auth authn.Authenticator
+}
+
+var _ authn.Keychain = googleKeychain{}
+
+// isGoogleRegistry matches the registries handled by google.Keychain.
+func isGoogleRegistry(host string) bool {
+	return host == "gcr.io" ||
+		strings.HasSuffix(host, ".gcr.io") ||
+		strings.HasSuffix(host, ".pkg.dev") ||
+		strings.HasSuffix(host, ".google.com")
+}
+
+func (k googleKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	if !isGoogleRegistry(target.RegistryStr()) {
+		return authn.Anonymous, nil
+	}
+	return k.auth, nil
+}
+
+// readCredentials returns value as is if it looks like JSON, otherwise it is
+// treated as a path and the contents of the file are returned.
+func readCredentials(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "{") {
+		return []byte(value), nil
+	}
+	contents, err := os.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credentials file %s: %w", value, err)
+	}
+	return contents, nil
+}
+
+// googleTokenSource mints Google access tokens for the given OAuth scopes.
+type googleTokenSource func(ctx context.Context, scopes []string) (oauth2.TokenSource, error)
+
+// credentialsTokenSource mints access tokens from a service account key
+// (JSON contents or path).
+func credentialsTokenSource(credentials string) (googleTokenSource, error) {
+	key, err := readCredentials(credentials)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := googauth.JWTConfigFromJSON(key, cloudPlatformScope); err != nil {
+		return nil, fmt.Errorf("unable to parse service account key: %w", err)
+	}
+	return func(ctx context.Context, scopes []string) (oauth2.TokenSource, error) {
+		cfg, err := googauth.JWTConfigFromJSON(key, scopes...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse service account key: %w", err)
+		}
+		return oauth2.ReuseTokenSource(nil, cfg.TokenSource(ctx)), nil
+	}, nil
+}
+
+// accessTokenSource returns a static OAuth access token, whatever the scopes.
+func accessTokenSource(token string) googleTokenSource {
+	return func(ctx context.Context, scopes []string) (oauth2.TokenSource, error) {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+	}
+}
+
+// defaultTokenSource mints access tokens from the application default
+// credentials.
+func defaultTokenSource(ctx context.Context, scopes []string) (oauth2.TokenSource, error) {
+	return googauth.DefaultTokenSource(ctx, scopes...)
+}
+
+// tokenSourceKeychain uses access tokens with the cloud-platform scope for
+// Google registries.
+func tokenSourceKeychain(source googleTokenSource) (authn.Keychain, error) {
+	ts, err := source(context.Background(), []string{cloudPlatformScope})
+	if err != nil {
+		return nil, err
+	}
+	return googleKeychain{auth: google.NewTokenSourceAuthenticator(ts)}, nil
+}
+
+// accessTokenKeychain uses a static OAuth access token for Google registries.
+func accessTokenKeychain(token string) authn.Keychain {
+	return googleKeychain{auth: authn.FromConfig(authn.AuthConfig{
+		Username: "_token",
+		Password: token,
+	})}
+}
+
+// staticSubjectToken supplies a fixed subject token for the token exchange.
+type staticSubjectToken string
+
+func (t staticSubjectToken) SubjectToken(ctx context.Context, options externalaccount.SupplierOptions) (string, error) {
+	return string(t), nil
+}
+
+// externalAccountTokenSource exchanges an external subject token for Google
+// access tokens using Workload Identity Federation.
+func externalAccountTokenSource(cfg GcraneProviderExternalAccountModel) (googleTokenSource, error) {
+	if cfg.Audience.ValueString() == "" {
+		return nil, fmt.Errorf("audience must be set")
+	}
+	conf := externalaccount.Config{
+		Audience:                       cfg.Audience.ValueString(),
+		SubjectTokenType:               cfg.SubjectTokenType.ValueString(),
+		TokenURL:                       cfg.TokenURL.ValueString(),
+		ServiceAccountImpersonationURL: cfg.ServiceAccountImpersonationURL.ValueString(),
+	}
+	if conf.SubjectTokenType == "" {
+		conf.SubjectTokenType = "urn:ietf:params:oauth:token-type:jwt"
+	}
+	switch {
+	case cfg.SubjectToken.ValueString() != "" && cfg.SubjectTokenFile.ValueString() != "":
+		return nil, fmt.Errorf("only one of subject_token and subject_token_file can be set")
+	case cfg.SubjectToken.ValueString() != "":
+		conf.SubjectTokenSupplier = staticSubjectToken(cfg.SubjectToken.ValueString())
+	case cfg.SubjectTokenFile.ValueString() != "":
+		conf.CredentialSource = &externalaccount.CredentialSource{File: cfg.SubjectTokenFile.ValueString()}
+	default:
+		return nil, fmt.Errorf("one of subject_token and subject_token_file must be set")
+	}
+
+	return func(ctx context.Context, scopes []string) (oauth2.TokenSource, error) {
+		conf := conf
+		conf.Scopes = scopes
+		ts, err := externalaccount.NewTokenSource(ctx, conf)
+		if err != nil {
+			return nil, err
+		}
+		return oauth2.ReuseTokenSource(nil, ts), nil
+	}, nil
+}
+
+// credentialHelperKeychain runs a Docker credential helper
+// (docker-credential-<name>) configured for a registry.
+type credentialHelperKeychain map[string]string
+
+var _ authn.Keychain = credentialHelperKeychain{}
+
+func (k credentialHelperKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	helper, ok := k[target.RegistryStr()]
+	if !ok {
+		return authn.Anonymous, nil
+	}
+	creds, err := client.Get(client.NewShellProgramFunc("docker-credential-"+helper), target.RegistryStr())
+	if err != nil {
+		if credentials.IsErrCredentialsNotFound(err) {
+			return authn.Anonymous, nil
+		}
+		return nil, fmt.Errorf("credential helper docker-credential-%s failed for %s: %w", helper, target.RegistryStr(), err)
+	}
+	// Identity tokens are returned with a special username, see:
+	// https://docs.docker.com/reference/cli/docker/login/#credential-helper-protocol
+	if creds.Username == "<token>" {
+		return authn.FromConfig(authn.AuthConfig{Username: creds.Username, IdentityToken: creds.Secret}), nil
+	}
+	return authn.FromConfig(authn.AuthConfig{Username: creds.Username, Password: creds.Secret}), nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// layerCache keeps compressed layers on disk, so copies sharing layers
+// download them from the source once. Layers are written to a temporary
+// file and only kept once complete and verified, and copies of a layer that
+// is being downloaded wait for the download to finish.
+type layerCache struct {
+	dir   string
+	mu    sync.Mutex
+	locks map[v1.Hash]*sync.Mutex
+}
+
+// newLayerCache returns a layer cache in dir, creating it if needed.
+func newLayerCache(dir string) (*layerCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create cache directory %s: %w", dir, err)
+	}
+	return &layerCache{dir: dir, locks: map[v1.Hash]*sync.Mutex{}}, nil
+}
+
+// lock returns the lock of a layer.
+func (c *layerCache) lock(digest v1.Hash) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	l, ok := c.locks[digest]
+	if !ok {
+		l = &sync.Mutex{}
+		c.locks[digest] = l
+	}
+	return l
+}
+
+func (c *layerCache) path(digest v1.Hash) string {
+	return filepath.Join(c.dir, digest.Algorithm+"-"+digest.Hex)
+}
+
+// Image returns img reading its layers through the cache.
+func (c *layerCache) Image(img v1.Image) v1.Image {
+	return &cachedImage{Image: img, cache: c}
+}
+
+// ImageIndex returns idx reading the layers of its images through the cache.
+func (c *layerCache) ImageIndex(idx v1.ImageIndex) v1.ImageIndex {
+	return &cachedIndex{inner: idx, cache: c}
+}
+
+type cachedIndex struct {
+	inner v1.ImageIndex
+	cache *layerCache
+}
+
+func (i *cachedIndex) MediaType() (ggcrtypes.MediaType, error)   { return i.inner.MediaType() }
+func (i *cachedIndex) Digest() (v1.Hash, error)                  { return i.inner.Digest() }
+func (i *cachedIndex) Size() (int64, error)                      { return i.inner.Size() }
+func (i *cachedIndex) IndexManifest() (*v1.IndexManifest, error) { return i.inner.IndexManifest() }
+func (i *cachedIndex) RawManifest() ([]byte, error)              { return i.inner.RawManifest() }
+
+func (i *cachedIndex) Image(h v1.Hash) (v1.Image, error) {
+	img, err := i.inner.Image(h)
+	if err != nil {
+		return nil, err
+	}
+	return i.cache.Image(img), nil
+}
+
+func (i *cachedIndex) ImageIndex(h v1.Hash) (v1.ImageIndex, error) {
+	idx, err := i.inner.ImageIndex(h)
+	if err != nil {
+		return nil, err
+	}
+	return i.cache.ImageIndex(idx), nil
+}
+
+// Layer returns a child of the index that is neither an image nor an index,
+// like the remote index does, so such children are copied as blobs.
+func (i *cachedIndex) Layer(h v1.Hash) (v1.Layer, error) {
+	inner, ok := i.inner.(interface {
+		Layer(v1.Hash) (v1.Layer, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("unable to read %s from the index", h)
+	}
+	return inner.Layer(h)
+}
+
+type cachedImage struct {
+	v1.Image
+	cache *layerCache
+}
+
+func (i *cachedImage) Layers() ([]v1.Layer, error) {
+	layers, err := i.Image.Layers()
+	if err != nil {
+		return nil, err
+	}
+	cached := make([]v1.Layer, 0, len(layers))
+	for _, l := range layers {
+		cached = append(cached, &cachedLayer{Layer: l, cache: i.cache})
+	}
+	return cached, nil
+}
+
+func (i *cachedImage) LayerByDigest(h v1.Hash) (v1.Layer, error) {
+	l, err := i.Image.LayerByDigest(h)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedLayer{Layer: l, cache: i.cache}, nil
+}
+
+func (i *cachedImage) LayerByDiffID(h v1.Hash) (v1.Layer, error) {
+	l, err := i.Image.LayerByDiffID(h)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedLayer{Layer: l, cache: i.cache}, nil
+}
+
+type cachedLayer struct {
+	v1.Layer
+	cache *layerCache
+}
+
+// Compressed reads the layer from the cache, or downloads it into the cache.
+// The layer stays locked until the returned reader is closed.
+func (l *cachedLayer) Compressed() (io.ReadCloser, error) {
+	digest, err := l.Layer.Digest()
+	if err != nil || digest.Algorithm != "sha256" {
+		return l.Layer.Compressed()
+	}
+
+	lock := l.cache.lock(digest)
+	lock.Lock()
+	if f, err := os.Open(l.cache.path(digest)); err == nil {
+		lock.Unlock()
+		return f, nil
+	}
+
+	rc, err := l.Layer.Compressed()
+	if err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(l.cache.dir, "download-*")
+	if err != nil {
+		lock.Unlock()
+		return rc, nil
+	}
+	return &cachingReader{
+		inner:  rc,
+		tmp:    tmp,
+		hash:   sha256.New(),
+		digest: digest,
+		path:   l.cache.path(digest),
+		unlock: lock.Unlock,
+	}, nil
+}
+
+// cachingReader copies a layer into a temporary file as it is read, and
+// moves it into the cache when the whole layer was read and matches its
+// digest.
+type cachingReader struct {
+	inner    io.ReadCloser
+	tmp      *os.File
+	hash     hash.Hash
+	digest   v1.Hash
+	path     string
+	complete bool
+	unlock   func()
+	once     sync.Once
+}
+
+func (r *cachingReader) Read(p []byte) (int, error) {
+	n, err := r.inner.Read(p)
+	if n > 0 && r.tmp != nil {
+		r.hash.Write(p[:n])
+		if _, writeErr := r.tmp.Write(p[:n]); writeErr != nil {
+			// Carry on without caching the layer.
+			r.tmp.Close()
+			os.Remove(r.tmp.Name())
+			r.tmp = nil
+		}
+	}
+	if err == io.EOF {
+		r.complete = true
+	}
+	return n, err
+}
+
+func (r *cachingReader) Close() error {
+	err := r.inner.Close()
+	r.once.Do(func() {
+		defer r.unlock()
+		if r.tmp == nil {
+			return
+		}
+		r.tmp.Close()
+		if r.complete && hex.EncodeToString(r.hash.Sum(nil)) == r.digest.Hex {
+			if os.Rename(r.tmp.Name(), r.path) == nil {
+				return
+			}
+		}
+		os.Remove(r.tmp.Name())
+	})
+	return err
+}
+
+// copyCached copies an image or image index like crane.Copy, reading the
+// layers through cache.
+func copyCached(src, dst string, cache *layerCache, opts ...remote.Option) error {
+	srcRef, err := name.ParseReference(src)
+	if err != nil {
+		return fmt.Errorf("parsing reference %q: %w", src, err)
+	}
+	dstRef, err := name.ParseReference(dst)
+	if err != nil {
+		return fmt.Errorf("parsing reference for %q: %w", dst, err)
+	}
+	desc, err := remote.Get(srcRef, opts...)
+	if err != nil {
+		return fmt.Errorf("fetching %q: %w", src, err)
+	}
+
+	switch {
+	case desc.MediaType.IsIndex():
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return err
+		}
+		return remote.WriteIndex(dstRef, cache.ImageIndex(idx), opts...)
+	case desc.MediaType.IsImage():
+		img, err := desc.Image()
+		if err != nil {
+			return err
+		}
+		return remote.Write(dstRef, cache.Image(img), opts...)
+	default:
+		return remote.Put(dstRef, desc, opts...)
+	}
+}
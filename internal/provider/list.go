@@ -0,0 +1,393 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// listFilter controls which manifests of a repository listing end up in state.
+type listFilter struct {
+	IncludeUntagged bool
+	// Limit is the maximum number of manifests to keep (0 means no limit).
+	Limit  int
+	Offset int
+	// SortBy is one of "created", "uploaded" (the default) or "tag".
+	SortBy     string
+	Descending bool
+	// GroupBy selects the tag groups reported in newest_per_group.
+	GroupBy *regexp.Regexp
+	// CreatedAfter and CreatedBefore bound the creation time (zero means unbounded).
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// key returns a canonical representation of the settings that affect which
+// manifests are kept.
+func (f listFilter) key() string {
+	sortBy := f.SortBy
+	if sortBy == "" {
+		sortBy = "uploaded"
+	}
+	return fmt.Sprintf("untagged=%t\nlimit=%d\noffset=%d\nsort=%s\ndesc=%t\nafter=%d\nbefore=%d",
+		f.IncludeUntagged, f.Limit, f.Offset, sortBy, f.Descending,
+		f.CreatedAfter.UnixMilli(), f.CreatedBefore.UnixMilli())
+}
+
+// matches reports whether a manifest passes the filter, regardless of order.
+func (f listFilter) matches(m google.ManifestInfo) bool {
+	if !f.IncludeUntagged && len(m.Tags) == 0 {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && !m.Created.After(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !m.Created.Before(f.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// before reports whether manifest a sorts before manifest b.
+func (f listFilter) before(digestA string, a google.ManifestInfo, digestB string, b google.ManifestInfo) bool {
+	var cmp int
+	switch f.SortBy {
+	case "created":
+		cmp = a.Created.Compare(b.Created)
+	case "tag":
+		cmp = strings.Compare(manifestSortTag(a), manifestSortTag(b))
+	default:
+		cmp = a.Uploaded.Compare(b.Uploaded)
+	}
+	if cmp == 0 {
+		cmp = strings.Compare(digestA, digestB)
+	}
+	if f.Descending {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// manifestSortTag returns the lowest tag of a manifest, used when sorting by tag.
+func manifestSortTag(m google.ManifestInfo) string {
+	if len(m.Tags) == 0 {
+		return ""
+	}
+	tags := append([]string{}, m.Tags...)
+	sort.Strings(tags)
+	return tags[0]
+}
+
+// filterManifests returns the digests of the manifests that pass the filter,
+// in sort order.
+func filterManifests(manifests map[string]google.ManifestInfo, filter listFilter) []string {
+	digests := make([]string, 0, len(manifests))
+	for k, v := range manifests {
+		if filter.matches(v) {
+			digests = append(digests, k)
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return filter.before(digests[i], manifests[digests[i]], digests[j], manifests[digests[j]])
+	})
+
+	if filter.Offset >= len(digests) {
+		return []string{}
+	}
+	digests = digests[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(digests) {
+		digests = digests[:filter.Limit]
+	}
+	return digests
+}
+
+// manifestHeap keeps the digests that sort first, with the one that sorts
+// last on top so it can be evicted.
+type manifestHeap struct {
+	filter    listFilter
+	digests   []string
+	manifests map[string]google.ManifestInfo
+}
+
+func (h *manifestHeap) Len() int { return len(h.digests) }
+func (h *manifestHeap) Less(i, j int) bool {
+	return h.filter.before(h.digests[j], h.manifests[h.digests[j]], h.digests[i], h.manifests[h.digests[i]])
+}
+func (h *manifestHeap) Swap(i, j int) { h.digests[i], h.digests[j] = h.digests[j], h.digests[i] }
+func (h *manifestHeap) Push(x any)    { h.digests = append(h.digests, x.(string)) }
+func (h *manifestHeap) Pop() any {
+	last := h.digests[len(h.digests)-1]
+	h.digests = h.digests[:len(h.digests)-1]
+	return last
+}
+
+// add keeps a manifest, evicting the one that sorts last once more than
+// capacity manifests are kept (0 means unbounded).
+func (h *manifestHeap) add(digest string, m google.ManifestInfo, capacity int) {
+	h.manifests[digest] = m
+	heap.Push(h, digest)
+	if capacity > 0 && h.Len() > capacity {
+		delete(h.manifests, heap.Pop(h).(string))
+	}
+}
+
+// listRepository lists a repository like google.List, but decodes the
+// response as it is read and only keeps the manifests that pass the filter.
+// When a limit is set only the offset+limit first manifests in sort order
+// are retained, so memory use does not grow with the size of the repository.
+// Unlike google.List, requests are only retried by the retry policy of base.
+func listRepository(ctx context.Context, repo name.Repository, filter listFilter, keychain authn.Keychain, base http.RoundTripper) (*google.Tags, error) {
+	auth, err := authn.Resolve(ctx, keychain, repo)
+	if err != nil {
+		return nil, err
+	}
+	tr, err := transport.NewWithContext(ctx, repo.Registry, auth, base, []string{repo.Scope(transport.PullScope)})
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{Transport: tr}
+
+	uri := &url.URL{
+		Scheme:   repo.Scheme(),
+		Host:     repo.RegistryStr(),
+		Path:     fmt.Sprintf("/v2/%s/tags/list", repo.RepositoryStr()),
+		RawQuery: "n=10000",
+	}
+	// ECR returns an error if n > 1000.
+	if !strings.HasSuffix(repo.RegistryStr(), "gcr.io") && !strings.HasSuffix(repo.RegistryStr(), "pkg.dev") {
+		uri.RawQuery = "n=1000"
+	}
+
+	capacity := 0
+	if filter.Limit > 0 {
+		capacity = filter.Offset + filter.Limit
+	}
+	kept := &manifestHeap{filter: filter, manifests: make(map[string]google.ManifestInfo, 0)}
+	tags := &google.Tags{}
+
+	for uri != nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := transport.CheckError(resp, http.StatusOK); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+
+		err = decodeTags(json.NewDecoder(resp.Body), tags, func(digest string, m google.ManifestInfo) {
+			if filter.matches(m) {
+				kept.add(digest, m, capacity)
+			}
+		})
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode listing of %s: %w", repo, err)
+		}
+
+		uri, err = nextPageURL(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tags.Manifests = kept.manifests
+	return tags, nil
+}
+
+// decodeTags decodes a single tags/list response into tags, passing each
+// manifest to fn instead of collecting them.
+func decodeTags(dec *json.Decoder, tags *google.Tags, fn func(digest string, m google.ManifestInfo)) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case "child":
+			var children []string
+			if err := dec.Decode(&children); err != nil {
+				return err
+			}
+			tags.Children = append(tags.Children, children...)
+		case "tags":
+			var t []string
+			if err := dec.Decode(&t); err != nil {
+				return err
+			}
+			tags.Tags = append(tags.Tags, t...)
+		case "name":
+			if err := dec.Decode(&tags.Name); err != nil {
+				return err
+			}
+		case "manifest":
+			if err := expectDelim(dec, '{'); err != nil {
+				return err
+			}
+			for dec.More() {
+				digest, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				var m google.ManifestInfo
+				if err := dec.Decode(&m); err != nil {
+					return err
+				}
+				fn(fmt.Sprint(digest), m)
+			}
+			if err := expectDelim(dec, '}'); err != nil {
+				return err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %s, got %v", delim, tok)
+	}
+	return nil
+}
+
+// nextPageURL returns the next page from the Link header of resp, if any.
+func nextPageURL(resp *http.Response) (*url.URL, error) {
+	link := resp.Header.Get("Link")
+	if link == "" {
+		return nil, nil
+	}
+	start := strings.Index(link, "<")
+	end := strings.Index(link, ">")
+	if start != 0 || end == -1 {
+		return nil, fmt.Errorf("failed to parse link header: %s", link)
+	}
+	linkURL, err := url.Parse(link[1:end])
+	if err != nil {
+		return nil, err
+	}
+	if resp.Request == nil || resp.Request.URL == nil {
+		return nil, nil
+	}
+	return resp.Request.URL.ResolveReference(linkURL), nil
+}
+
+// walkRepositories lists root and all of its child repositories recursively,
+// listing at most jobs repositories concurrently. The child listings are keyed
+// by full repository name. Listings are passed a context that is cancelled
+// when the walk fails.
+func walkRepositories(ctx context.Context, root name.Repository, jobs int, list func(context.Context, name.Repository) (*google.Tags, error)) (*google.Tags, map[string]*google.Tags, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rootTags, err := list(ctx, root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	children := make(map[string]*google.Tags, 0)
+	sem := make(chan struct{}, jobs)
+
+	var visit func(repo name.Repository, tags *google.Tags)
+	visit = func(repo name.Repository, tags *google.Tags) {
+		for _, child := range tags.Children {
+			childRepo, err := name.NewRepository(fmt.Sprintf("%s/%s", repo, child), name.StrictValidation)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("unexpected child repository %s/%s: %w", repo, child, err)
+					cancel()
+				}
+				mu.Unlock()
+				return
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				// Both cases can be ready at once, so the walk may have
+				// failed while waiting.
+				if ctx.Err() != nil {
+					<-sem
+					return
+				}
+				childTags, err := list(ctx, childRepo)
+
+				// The walk is cancelled before the next listing can start.
+				mu.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to list child repository %s: %w", childRepo, err)
+						cancel()
+					}
+					mu.Unlock()
+					<-sem
+					return
+				}
+				children[childRepo.String()] = childTags
+				mu.Unlock()
+				<-sem
+
+				visit(childRepo, childTags)
+			}()
+		}
+	}
+	visit(root, rootTags)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	return rootTags, children, nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ list.ListResource = &CopyListResource{}
+var _ list.ListResourceWithConfigure = &CopyListResource{}
+
+func NewCopyListResource() list.ListResource {
+	return &CopyListResource{}
+}
+
+// CopyListResource lists the tags of a repository as gcrane_copy resources.
+type CopyListResource struct {
+	Client *GcraneData
+}
+
+// CopyListResourceModel describes the list resource configuration.
+type CopyListResourceModel struct {
+	Repository       types.String `tfsdk:"repository"`
+	SourceRepository types.String `tfsdk:"source_repository"`
+}
+
+func (r *CopyListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_copy"
+}
+
+func (r *CopyListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the tags of a repository as `gcrane_copy` resources, for example to generate configuration for importing images that were already mirrored",
+		Description:         "Lists the tags of a repository as gcrane_copy resources",
+		Attributes: map[string]schema.Attribute{
+			"repository": schema.StringAttribute{
+				MarkdownDescription: "Repository to list the tags of (the destination of the copies)",
+				Required:            true,
+			},
+			"source_repository": schema.StringAttribute{
+				MarkdownDescription: "Repository the images were copied from. When set, the `source` of each copy is the same tag in this repository",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *CopyListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected List Resource Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = client
+}
+
+func (r *CopyListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var data CopyListResourceModel
+
+	diags := req.Config.Get(ctx, &data)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	repo, err := name.NewRepository(data.Repository.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("repository"), "Invalid repository", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	var source *name.Repository
+	if !data.SourceRepository.IsNull() {
+		sourceRepo, err := name.NewRepository(data.SourceRepository.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("source_repository"), "Invalid repository", err.Error())
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+		source = &sourceRepo
+	}
+
+	tags, diags := r.listTags(ctx, repo)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for i, tag := range tags {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+
+			destination := repo.Tag(tag).String()
+			result := req.NewListResult(ctx)
+			result.DisplayName = destination
+			result.Diagnostics.Append(result.Identity.Set(ctx, CopyResourceIdentityModel{
+				Destination: types.StringValue(destination),
+			})...)
+			if req.IncludeResource {
+				model := CopyResourceModel{
+					Recursive:           types.BoolNull(),
+					Jobs:                types.Int64Null(),
+					MaxBandwidthMbps:    types.Float64Null(),
+					DryRun:              types.BoolNull(),
+					IgnoreMissingSource: types.BoolNull(),
+					ContinueOnError:     types.BoolNull(),
+					Failures:            types.ListNull(types.ObjectType{AttrTypes: CopyResourceFailureModel{}.AttributeTypes()}),
+					Platform:            types.StringNull(),
+					Source:              types.StringNull(),
+					Destination:         types.StringValue(destination),
+					Digest:              types.StringNull(),
+					DigestsMatch:        types.BoolNull(),
+					BlobsMounted:        types.Int64Null(),
+					Id:                  types.StringValue(destination),
+				}
+				if source != nil {
+					model.Source = types.StringValue(source.Tag(tag).String())
+				}
+				result.Diagnostics.Append(result.Resource.Set(ctx, model)...)
+			}
+			if !push(result) {
+				return
+			}
+		}
+	}
+}
+
+// listTags returns the tags of repo in sort order.
+func (r *CopyListResource) listTags(ctx context.Context, repo name.Repository) (tags []string, diags diag.Diagnostics) {
+	ctx, span := r.Client.Tracer.Start(ctx, "list", attribute.String("gcrane.repository", repo.String()))
+	defer func() { span.End(ctx, diags) }()
+
+	err := r.Client.Setup(ctx, r.Client)
+	if err != nil {
+		diags.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return nil, diags
+	}
+	defer func() {
+		err := r.Client.Cleanup(ctx, r.Client)
+		if err != nil {
+			diags.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	tags, err = remote.List(repo, remote.WithContext(ctx), remote.WithAuthFromKeychain(r.Client.Keychain), remote.WithTransport(r.Client.Transport))
+	if err != nil {
+		diags.AddError(
+			"Could not list repository",
+			fmt.Sprintf("Error when listing %s: %s", repo, registryErrorDetail(err, r.Client.Failures)),
+		)
+		return nil, diags
+	}
+	sort.Strings(tags)
+	return tags, diags
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/querycheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccCopyListResource(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.SeedRandomImage(t, "pause:latest")
+
+	resource.Test(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_14_0),
+		},
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Query:  true,
+				Config: testAccCopyListResourceConfig(registry.Ref("pause")),
+				QueryResultChecks: []querycheck.QueryResultCheck{
+					querycheck.ExpectLengthAtLeast("gcrane_copy.pause", 1),
+					querycheck.ExpectIdentity("gcrane_copy.pause", map[string]knownvalue.Check{
+						"destination": knownvalue.StringExact(registry.Ref("pause:latest")),
+					}),
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyListResourceConfig(repository string) string {
+	return fmt.Sprintf(`
+provider "gcrane" {}
+
+list "gcrane_copy" "pause" {
+  provider = gcrane
+
+  config {
+    repository = "%s"
+  }
+}
+`, repository)
+}
@@ -0,0 +1,257 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+)
+
+// testDigest returns a distinct digest for i.
+func testDigest(i int) string {
+	return fmt.Sprintf("sha256:%064x", i)
+}
+
+// testManifests returns n manifests uploaded (and created) a minute apart,
+// with the digest of manifest i being testDigest(i). Manifests are tagged
+// with their index, except the untagged ones.
+func testManifests(n int, untagged ...int) map[string]google.ManifestInfo {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	manifests := make(map[string]google.ManifestInfo, n)
+	for i := 0; i < n; i++ {
+		m := google.ManifestInfo{
+			Size:      uint64(1000 + i),
+			MediaType: "application/vnd.oci.image.manifest.v1+json",
+			Created:   start.Add(time.Duration(i) * time.Minute),
+			Uploaded:  start.Add(time.Duration(i) * time.Minute),
+			Tags:      []string{fmt.Sprintf("v%d", i)},
+		}
+		for _, u := range untagged {
+			if u == i {
+				m.Tags = nil
+			}
+		}
+		manifests[testDigest(i)] = m
+	}
+	return manifests
+}
+
+// countingTransport counts the tags/list requests made through it.
+type countingTransport struct {
+	lists atomic.Int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/tags/list") {
+		t.lists.Add(1)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestListRepositoryPages(t *testing.T) {
+	registry := newTestListingRegistry(t, 2)
+	manifests := testManifests(5, 2)
+	registry.SetListing("project/app", &google.Tags{
+		Tags:      []string{"v0", "v1", "v3", "v4"},
+		Children:  []string{"cache", "tools"},
+		Manifests: manifests,
+	})
+	repo, err := name.NewRepository(registry.Ref("project/app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := &countingTransport{}
+	tags, err := listRepository(context.Background(), repo, listFilter{IncludeUntagged: true}, authn.DefaultKeychain, transport)
+	if err != nil {
+		t.Fatalf("listRepository() failed: %v", err)
+	}
+	if got := transport.lists.Load(); got != 3 {
+		t.Errorf("listed %d pages, want 3", got)
+	}
+	if !reflect.DeepEqual(tags.Tags, []string{"v0", "v1", "v3", "v4"}) {
+		t.Errorf("tags = %v, want the tags of the first page", tags.Tags)
+	}
+	if !reflect.DeepEqual(tags.Children, []string{"cache", "tools"}) {
+		t.Errorf("children = %v, want [cache tools]", tags.Children)
+	}
+	if len(tags.Manifests) != len(manifests) {
+		t.Fatalf("got %d manifests, want the %d manifests of all pages", len(tags.Manifests), len(manifests))
+	}
+	for digest, m := range manifests {
+		got, ok := tags.Manifests[digest]
+		if !ok {
+			t.Errorf("manifest %s is missing", digest)
+			continue
+		}
+		if !got.Uploaded.Equal(m.Uploaded) || !reflect.DeepEqual(got.Tags, m.Tags) {
+			t.Errorf("manifest %s = %+v, want %+v", digest, got, m)
+		}
+	}
+}
+
+func TestListRepositoryLimitOffset(t *testing.T) {
+	registry := newTestListingRegistry(t, 3)
+	registry.SetListing("project/app", &google.Tags{Manifests: testManifests(8, 6)})
+	repo, err := name.NewRepository(registry.Ref("project/app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter listFilter
+		want   []string
+	}{
+		{
+			name:   "newest first",
+			filter: listFilter{IncludeUntagged: true, Limit: 2, Offset: 1, Descending: true},
+			want:   []string{testDigest(6), testDigest(5)},
+		},
+		{
+			name:   "newest tagged first",
+			filter: listFilter{Limit: 2, Offset: 1, Descending: true},
+			want:   []string{testDigest(5), testDigest(4)},
+		},
+		{
+			name:   "oldest first",
+			filter: listFilter{IncludeUntagged: true, Limit: 3},
+			want:   []string{testDigest(0), testDigest(1), testDigest(2)},
+		},
+		{
+			name:   "by tag",
+			filter: listFilter{SortBy: "tag", Limit: 2, Offset: 2},
+			want:   []string{testDigest(2), testDigest(3)},
+		},
+		{
+			name:   "offset past the end",
+			filter: listFilter{IncludeUntagged: true, Limit: 2, Offset: 8},
+			want:   []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := listRepository(context.Background(), repo, tt.filter, authn.DefaultKeychain, http.DefaultTransport)
+			if err != nil {
+				t.Fatalf("listRepository() failed: %v", err)
+			}
+			// Only the manifests up to offset+limit are kept while listing.
+			if capacity := tt.filter.Offset + tt.filter.Limit; len(tags.Manifests) > capacity {
+				t.Errorf("kept %d manifests, want at most %d", len(tags.Manifests), capacity)
+			}
+			if got := filterManifests(tags.Manifests, tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterManifests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWalkRepositoriesError(t *testing.T) {
+	root, err := name.NewRepository("registry.example.com/project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	children := []string{"broken"}
+	for i := 0; i < 20; i++ {
+		children = append(children, fmt.Sprintf("app%d", i))
+	}
+
+	var (
+		lock   sync.Mutex
+		listed []string
+		ctxs   []context.Context
+	)
+	list := func(ctx context.Context, repo name.Repository) (*google.Tags, error) {
+		lock.Lock()
+		listed = append(listed, repo.RepositoryStr())
+		ctxs = append(ctxs, ctx)
+		lock.Unlock()
+		switch repo.RepositoryStr() {
+		case "project":
+			return &google.Tags{Children: children}, nil
+		case "project/broken":
+			return nil, fmt.Errorf("listing failed")
+		}
+		if strings.Count(repo.RepositoryStr(), "/") == 1 {
+			return &google.Tags{Children: []string{"nested"}}, nil
+		}
+		return &google.Tags{}, nil
+	}
+
+	_, _, err = walkRepositories(context.Background(), root, 1, list)
+	if err == nil || !strings.Contains(err.Error(), "project/broken") {
+		t.Fatalf("walkRepositories() error = %v, want the error of project/broken", err)
+	}
+	// With one job, no listing starts after the failed one.
+	if last := listed[len(listed)-1]; last != "project/broken" {
+		sort.Strings(listed)
+		t.Errorf("listed %s after the failed listing (listed %v)", last, listed)
+	}
+	// Listings still running when the walk fails are cancelled.
+	for i, ctx := range ctxs {
+		if ctx.Err() == nil {
+			t.Errorf("listing of %s was not cancelled", listed[i])
+		}
+	}
+}
+
+func TestWalkRepositories(t *testing.T) {
+	root, err := name.NewRepository("registry.example.com/project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := func(ctx context.Context, repo name.Repository) (*google.Tags, error) {
+		switch repo.RepositoryStr() {
+		case "project":
+			return &google.Tags{Children: []string{"app", "tools"}}, nil
+		case "project/app":
+			return &google.Tags{Children: []string{"cache"}, Tags: []string{"latest"}}, nil
+		default:
+			return &google.Tags{}, nil
+		}
+	}
+
+	rootTags, children, err := walkRepositories(context.Background(), root, 2, list)
+	if err != nil {
+		t.Fatalf("walkRepositories() failed: %v", err)
+	}
+	if !reflect.DeepEqual(rootTags.Children, []string{"app", "tools"}) {
+		t.Errorf("root children = %v", rootTags.Children)
+	}
+	got := make([]string, 0, len(children))
+	for k := range children {
+		got = append(got, k)
+	}
+	sort.Strings(got)
+	want := []string{
+		"registry.example.com/project/app",
+		"registry.example.com/project/app/cache",
+		"registry.example.com/project/tools",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("child repositories = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultProgressInterval is used when progress_interval is not set.
+const defaultProgressInterval = 10 * time.Second
+
+// progressReporter logs structured progress events of a long running
+// operation, like a recursive copy, at most once per interval. Reporting to
+// a nil progressReporter does nothing.
+type progressReporter struct {
+	ctx       context.Context
+	operation string
+	interval  time.Duration
+	total     atomic.Int64
+	completed atomic.Int64
+
+	lock sync.Mutex
+	last time.Time
+}
+
+type progressKey struct{}
+
+// withProgress returns a context whose long running operations report to
+// progress.
+func withProgress(ctx context.Context, progress *progressReporter) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+// progressFrom returns the progress reporter of ctx, or nil.
+func progressFrom(ctx context.Context) *progressReporter {
+	progress, _ := ctx.Value(progressKey{}).(*progressReporter)
+	return progress
+}
+
+// Progress returns a context reporting the progress of operation (like
+// "copy") at the configured progress_interval, and its reporter.
+func (g *GcraneData) Progress(ctx context.Context, operation string) (context.Context, *progressReporter) {
+	progress := &progressReporter{
+		ctx:       ctx,
+		operation: operation,
+		interval:  g.ProgressInterval,
+		last:      time.Now(),
+	}
+	return withProgress(ctx, progress), progress
+}
+
+// AddTotal adds n items to the number of items the operation processes.
+func (p *progressReporter) AddTotal(n int) {
+	if p == nil {
+		return
+	}
+	p.total.Add(int64(n))
+}
+
+// Complete records item (like an image reference) as processed, and logs
+// an event if none was logged during the interval.
+func (p *progressReporter) Complete(item string) {
+	if p == nil {
+		return
+	}
+	completed := p.completed.Add(1)
+
+	p.lock.Lock()
+	now := time.Now()
+	due := now.Sub(p.last) >= p.interval
+	if due {
+		p.last = now
+	}
+	p.lock.Unlock()
+
+	if due {
+		p.log("Operation in progress", item, completed)
+	}
+}
+
+// Finish logs the final event of the operation.
+func (p *progressReporter) Finish() {
+	if p == nil {
+		return
+	}
+	p.log("Operation finished", "", p.completed.Load())
+}
+
+// log logs an event with the operation, the last processed item and the
+// number of processed items, and the percentage when the total is known.
+func (p *progressReporter) log(message string, item string, completed int64) {
+	fields := map[string]interface{}{
+		"operation": p.operation,
+		"completed": completed,
+	}
+	if item != "" {
+		fields["item"] = item
+	}
+	if total := p.total.Load(); total > 0 {
+		fields["total"] = total
+		fields["percent"] = math.Round(float64(completed)*1000/float64(total)) / 10
+	}
+	tflog.Info(p.ctx, message, fields)
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+// tagProtection lists the glob patterns of tags that are never deleted.
+type tagProtection struct {
+	patterns []destinationPattern
+}
+
+// newTagProtection compiles the protected tag patterns.
+func newTagProtection(globs []string) (tagProtection, error) {
+	patterns, err := compileGlobs(globs)
+	if err != nil {
+		return tagProtection{}, err
+	}
+	return tagProtection{patterns: patterns}, nil
+}
+
+// Enabled reports whether any tags are protected.
+func (p tagProtection) Enabled() bool {
+	return len(p.patterns) > 0
+}
+
+// Match returns the first pattern matching tag.
+func (p tagProtection) Match(tag string) (string, bool) {
+	for _, pattern := range p.patterns {
+		if pattern.re.MatchString(tag) {
+			return pattern.glob, true
+		}
+	}
+	return "", false
+}
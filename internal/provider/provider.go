@@ -17,20 +17,34 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/action"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+
 	"crypto/rand"
 )
 
@@ -38,6 +52,8 @@ import (
 var _ provider.Provider = &GcraneProvider{}
 var _ provider.ProviderWithFunctions = &GcraneProvider{}
 var _ provider.ProviderWithEphemeralResources = &GcraneProvider{}
+var _ provider.ProviderWithActions = &GcraneProvider{}
+var _ provider.ProviderWithListResources = &GcraneProvider{}
 
 // GcraneProvider defines the provider implementation.
 type GcraneProvider struct {
@@ -49,10 +65,82 @@ type GcraneProvider struct {
 
 // GcraneProviderModel describes the provider data model.
 type GcraneProviderModel struct {
-	DockerConfig types.String `tfsdk:"docker_config"`
-	TempDir      types.String `tfsdk:"temporary_directory"`
+	DockerConfig        types.String                        `tfsdk:"docker_config"`
+	DockerConfigPath    types.String                        `tfsdk:"docker_config_file"`
+	TempDir             types.String                        `tfsdk:"temporary_directory"`
+	Credentials         types.String                        `tfsdk:"credentials"`
+	AccessToken         types.String                        `tfsdk:"access_token"`
+	BillingProject      types.String                        `tfsdk:"billing_project"`
+	ExternalAccount     *GcraneProviderExternalAccountModel `tfsdk:"external_account"`
+	GithubToken         types.String                        `tfsdk:"github_token"`
+	CredentialHelpers   types.Map                           `tfsdk:"credential_helpers"`
+	Keychains           types.List                          `tfsdk:"keychains"`
+	CACertificates      types.List                          `tfsdk:"ca_certificates"`
+	InsecureSkipVerify  types.List                          `tfsdk:"insecure_skip_verify"`
+	PlainHTTPRegistries types.List                          `tfsdk:"plain_http_registries"`
+	HTTPProxy           types.String                        `tfsdk:"http_proxy"`
+	HTTPSProxy          types.String                        `tfsdk:"https_proxy"`
+	NoProxy             types.String                        `tfsdk:"no_proxy"`
+	UserAgent           types.String                        `tfsdk:"user_agent"`
+	TracingEndpoint     types.String                        `tfsdk:"tracing_endpoint"`
+	ProgressInterval    types.String                        `tfsdk:"progress_interval"`
+	Headers             types.Map                           `tfsdk:"headers"`
+	DebugHTTP           types.Bool                          `tfsdk:"debug_http"`
+	DefaultJobs         types.Int64                         `tfsdk:"default_jobs"`
+	DefaultPlatform     types.String                        `tfsdk:"default_platform"`
+	ReadOnly            types.Bool                          `tfsdk:"read_only"`
+	RegistryMirrors     types.Map                           `tfsdk:"registry_mirrors"`
+	AuditLogPath        types.String                        `tfsdk:"audit_log_path"`
+	AllowedDestinations types.List                          `tfsdk:"allowed_destinations"`
+	DeniedDestinations  types.List                          `tfsdk:"denied_destinations"`
+	RequestsPerSecond   types.Float64                       `tfsdk:"requests_per_second"`
+	MaxBandwidthMbps    types.Float64                       `tfsdk:"max_bandwidth_mbps"`
+	CacheDir            types.String                        `tfsdk:"cache_dir"`
+	ProtectTags         types.List                          `tfsdk:"protect_tags"`
+	HTTPTimeouts        *GcraneProviderHTTPTimeoutsModel    `tfsdk:"http_timeouts"`
+	Retries             *GcraneProviderRetriesModel         `tfsdk:"retries"`
+	RegistryAuth        []GcraneProviderRegistryAuthModel   `tfsdk:"registry_auth"`
+}
+
+// GcraneProviderHTTPTimeoutsModel describes the registry connection timeouts.
+type GcraneProviderHTTPTimeoutsModel struct {
+	Dial                      types.String `tfsdk:"dial"`
+	TLSHandshake              types.String `tfsdk:"tls_handshake"`
+	ResponseHeader            types.String `tfsdk:"response_header"`
+	IdleConnection            types.String `tfsdk:"idle_connection"`
+	MaxIdleConnectionsPerHost types.Int64  `tfsdk:"max_idle_connections_per_host"`
+}
+
+// GcraneProviderRetriesModel describes the retry policy for registry requests.
+type GcraneProviderRetriesModel struct {
+	MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+	MinBackoff  types.String `tfsdk:"min_backoff"`
+	MaxBackoff  types.String `tfsdk:"max_backoff"`
+	RetryOn     types.List   `tfsdk:"retry_on"`
+}
+
+// GcraneProviderExternalAccountModel describes Workload Identity Federation settings.
+type GcraneProviderExternalAccountModel struct {
+	Audience                       types.String `tfsdk:"audience"`
+	SubjectToken                   types.String `tfsdk:"subject_token"`
+	SubjectTokenFile               types.String `tfsdk:"subject_token_file"`
+	SubjectTokenType               types.String `tfsdk:"subject_token_type"`
+	ServiceAccountImpersonationURL types.String `tfsdk:"service_account_impersonation_url"`
+	TokenURL                       types.String `tfsdk:"token_url"`
+}
+
+// GcraneProviderRegistryAuthModel describes credentials for a single registry.
+type GcraneProviderRegistryAuthModel struct {
+	Address   types.String `tfsdk:"address"`
+	Username  types.String `tfsdk:"username"`
+	Password  types.String `tfsdk:"password"`
+	AuthToken types.String `tfsdk:"auth_token"`
 }
 
+// defaultKeychainOrder is the order credential sources are tried in unless
+// configured with the keychains attribute.
+var defaultKeychainOrder = []string{"registry_auth", "credential_helpers", "google", "docker_config", "ecr"}
+
 type GcraneData struct {
 	DockerConfig       string
 	DockerConfigFile   string
@@ -62,6 +150,104 @@ type GcraneData struct {
 	Setup              func(ctx context.Context, data interface{}) error
 	Cleanup            func(ctx context.Context, data interface{}) error
 	Counter            atomic.Int32
+	Keychain           authn.Keychain
+	Transport          http.RoundTripper
+	DefaultJobs        int
+	DefaultPlatform    *v1.Platform
+	ReadOnly           bool
+	Destinations       destinationPolicy
+	ProtectedTags      tagProtection
+	AuditLog           *auditLog
+	Tracer             *tracer
+	ProgressInterval   time.Duration
+	Mirrors            map[string]string
+	GoogleTokenSource  googleTokenSource
+	BillingProject     string
+	Registries         []string
+	Failures           *failureLog
+	LayerCache         *layerCache
+	ListCache          map[string]*listCacheEntry
+	ListCacheLock      sync.Mutex
+}
+
+// Jobs returns the configured concurrency, or the provider default when
+// jobs is not set.
+func (g *GcraneData) Jobs(jobs types.Int64) int {
+	if jobs.IsNull() || jobs.IsUnknown() {
+		return g.DefaultJobs
+	}
+	return int(jobs.ValueInt64())
+}
+
+// Mirror returns the source reference rewritten to the configured mirror
+// of its registry, if any.
+func (g *GcraneData) Mirror(source string) (string, bool) {
+	ref, err := name.ParseReference(source)
+	if err != nil {
+		return "", false
+	}
+	mirror, ok := g.Mirrors[ref.Context().RegistryStr()]
+	if !ok {
+		return "", false
+	}
+	mirrored := mirror + "/" + ref.Context().RepositoryStr()
+	if digest, ok := ref.(name.Digest); ok {
+		return mirrored + "@" + digest.DigestStr(), true
+	}
+	return mirrored + ":" + ref.Identifier(), true
+}
+
+// CheckWritable returns an error diagnostic for operation when the provider
+// is configured as read only.
+func (g *GcraneData) CheckWritable(operation string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if g.ReadOnly {
+		diags.AddError(
+			"Provider is read only",
+			fmt.Sprintf("Refusing to %s because the provider is configured with read_only = true.", operation),
+		)
+	}
+	return diags
+}
+
+// listCacheEntry holds a (possibly in-flight) repository listing.
+type listCacheEntry struct {
+	ready chan struct{}
+	tags  *google.Tags
+	err   error
+}
+
+// CachedList returns the result of list, reusing the result of any earlier
+// successful listing with the same key made through this provider instance.
+func (g *GcraneData) CachedList(key string, list func() (*google.Tags, error)) (*google.Tags, error) {
+	g.ListCacheLock.Lock()
+	if g.ListCache == nil {
+		g.ListCache = make(map[string]*listCacheEntry, 0)
+	}
+	entry, ok := g.ListCache[key]
+	if !ok {
+		entry = &listCacheEntry{ready: make(chan struct{})}
+		g.ListCache[key] = entry
+	}
+	g.ListCacheLock.Unlock()
+
+	if ok {
+		<-entry.ready
+		if entry.err == nil {
+			return entry.tags, nil
+		}
+		return list()
+	}
+
+	entry.tags, entry.err = list()
+	if entry.err != nil {
+		// Do not cache failures, the next caller will try again.
+		g.ListCacheLock.Lock()
+		delete(g.ListCache, key)
+		g.ListCacheLock.Unlock()
+	}
+	close(entry.ready)
+	return entry.tags, entry.err
 }
 
 func (p *GcraneProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -78,41 +264,631 @@ Allows copying images between Docker registries and also fetching some details (
 Does not require gcrane or Docker installed. You can specify a Docker config JSON file as a string
 in the provider configuration block, which will then be used during operations.
 
+Amazon ECR registries are authenticated using the standard AWS SDK credentials when
+no other credentials are configured for them.
+
+Credentials in the provider configuration may be set from ephemeral values (for example
+from an ephemeral resource or an ephemeral variable), so they are never persisted in the
+state or plan files.
+
+When the provider configuration depends on values that are not known yet (for example a
+docker_config built from a resource that has not been created), Terraform versions supporting
+deferred actions defer the resources using this provider to a later plan.
+
 This is a
 [community maintained provider](https://www.terraform.io/docs/providers/type/community-index.html)
 and not an official Google or Hashicorp product.
 		`,
 		Attributes: map[string]schema.Attribute{
+			"debug_http": schema.BoolAttribute{
+				MarkdownDescription: "Log the method, URL, status, headers and duration of every registry request at the `DEBUG` level (`TF_LOG=DEBUG`). Credentials are redacted",
+				Optional:            true,
+			},
+			"default_jobs": schema.Int64Attribute{
+				MarkdownDescription: "Default number of concurrent registry operations for copies and listings, unless overridden with `jobs` (defaults to the number of CPUs)",
+				Optional:            true,
+			},
+			"default_platform": schema.StringAttribute{
+				MarkdownDescription: "Platform (for example `linux/amd64`) used when a reference points at an image index: `gcrane_copy` copies only the image for this platform and `gcrane_list` describes it with `fetch_manifests`, unless overridden. By default whole indexes are used",
+				Optional:            true,
+			},
+			"denied_destinations": schema.ListAttribute{
+				MarkdownDescription: "Glob patterns of destinations that may never be written to, taking precedence over `allowed_destinations`",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
 			"docker_config": schema.StringAttribute{
 				MarkdownDescription: "Contents of Docker config file (JSON)",
 				Optional:            true,
+				Sensitive:           true,
+			},
+			"docker_config_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a Docker config file (JSON), as an alternative to `docker_config`",
+				Optional:            true,
+			},
+			"credentials": schema.StringAttribute{
+				MarkdownDescription: "Google service account key (JSON contents or a path to the file) used to authenticate to `gcr.io` and `pkg.dev` registries",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"access_token": schema.StringAttribute{
+				MarkdownDescription: "OAuth access token used to authenticate to `gcr.io` and `pkg.dev` registries, as an alternative to `credentials`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"billing_project": schema.StringAttribute{
+				MarkdownDescription: "Project billed for the quota of Google API calls, like the Artifact Registry API used by `gcrane_artifact_registry_repositories`, sent as the `X-Goog-User-Project` header. Required by some organizations when using user credentials. Not used for registry requests",
+				Optional:            true,
+			},
+			"github_token": schema.StringAttribute{
+				MarkdownDescription: "GitHub token (personal access token or `GITHUB_TOKEN`) used to authenticate to `ghcr.io`, unless a `registry_auth` block is configured for it",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"allowed_destinations": schema.ListAttribute{
+				MarkdownDescription: "Glob patterns (for example `europe-docker.pkg.dev/staging-*/**`) of destinations that may be written to. Plans writing anywhere else fail. `*` matches within a path segment and `**` across segments. Destinations are matched fully qualified, so `docker.io/myorg/**` also matches `myorg/app`",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"audit_log_path": schema.StringAttribute{
+				MarkdownDescription: "File to append a JSON line to for every registry write (time, user, host, operation, source and its digest, destination and result)",
+				Optional:            true,
+			},
+			"ca_certificates": schema.ListAttribute{
+				MarkdownDescription: "Additional CA certificates (PEM contents or paths to PEM files) trusted for registry connections, on top of the system trust store",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"credential_helpers": schema.MapAttribute{
+				MarkdownDescription: "Docker credential helpers to run per registry (for example `{ \"europe-docker.pkg.dev\" = \"gcloud\" }` runs `docker-credential-gcloud`), without needing a Docker config file",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"headers": schema.MapAttribute{
+				MarkdownDescription: "Extra HTTP headers sent with every registry request",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"http_proxy": schema.StringAttribute{
+				MarkdownDescription: "Proxy used for plain HTTP registry requests. When any of `http_proxy`, `https_proxy` or `no_proxy` is set, the proxy environment variables are ignored",
+				Optional:            true,
+			},
+			"https_proxy": schema.StringAttribute{
+				MarkdownDescription: "Proxy used for HTTPS registry requests",
+				Optional:            true,
+			},
+			"insecure_skip_verify": schema.ListAttribute{
+				MarkdownDescription: "Registries (for example `registry.internal:5000`) whose TLS certificates are not verified. Verification stays on for all other registries",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"keychains": schema.ListAttribute{
+				MarkdownDescription: "Order in which credential sources are tried for each registry: `registry_auth` (including `github_token`), `credential_helpers`, `google` (configured Google credentials, then application default credentials), `docker_config` and `ecr`. Sources after `anonymous` and sources not listed are never used. Defaults to all sources in the order above.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"no_proxy": schema.StringAttribute{
+				MarkdownDescription: "Comma-separated list of hosts, domains and CIDR ranges that are connected to directly instead of through the proxy",
+				Optional:            true,
+			},
+			"plain_http_registries": schema.ListAttribute{
+				MarkdownDescription: "Registries (for example `registry.dev:5000`) that are served over plain HTTP instead of HTTPS. `localhost` registries already use HTTP",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"user_agent": schema.StringAttribute{
+				MarkdownDescription: "Suffix appended to the User-Agent of every registry request, for example to attribute traffic to a pipeline",
+				Optional:            true,
+			},
+			"tracing_endpoint": schema.StringAttribute{
+				MarkdownDescription: "OTLP/HTTP endpoint (like `http://localhost:4318`) to export OpenTelemetry traces of registry operations to, with a span per copy, list and delete and per registry request (like a manifest `HEAD`), including image references and byte counts. Defaults to the `OTEL_EXPORTER_OTLP_TRACES_ENDPOINT` or `OTEL_EXPORTER_OTLP_ENDPOINT` environment variables. Traces are not exported when neither is set",
+				Optional:            true,
+			},
+			"progress_interval": schema.StringAttribute{
+				MarkdownDescription: "How often long running operations (like recursive copies and garbage collection) log their progress, with the operation, the last processed item and the percentage done, as a duration like `30s`. Defaults to `10s`, `0s` logs every item. Progress is logged at the `INFO` level",
+				Optional:            true,
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Fail any operation that writes to a registry (like creating a `gcrane_copy`), so configurations can be planned and applied in audit environments without pushing anything",
+				Optional:            true,
+			},
+			"registry_mirrors": schema.MapAttribute{
+				MarkdownDescription: "Mirrors (or pull-through caches) to pull copy sources from, by source registry (for example `{ \"docker.io\" = \"mirror.gcr.io\" }`). Images missing from the mirror are copied from the source registry. Not used for recursive copies",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum number of registry requests per second, across all resources and data sources (including retries). Useful to stay under Docker Hub pull quotas. Unlimited by default",
+				Optional:            true,
+			},
+			"max_bandwidth_mbps": schema.Float64Attribute{
+				MarkdownDescription: "Maximum bandwidth for registry transfers in megabits per second, for uploads and downloads each, across all resources and data sources. Copies can override it with their own `max_bandwidth_mbps`. Unlimited by default",
+				Optional:            true,
+			},
+			"cache_dir": schema.StringAttribute{
+				MarkdownDescription: "Directory to cache layers in, so layers shared by copies are downloaded from the source once. Only used for copies between registries. The directory is not cleaned up by the provider",
+				Optional:            true,
+			},
+			"protect_tags": schema.ListAttribute{
+				MarkdownDescription: "Glob patterns of tags (for example `prod-*`, `release-*` or `latest`) that are never deleted: `gcrane_delete` refuses to delete them or the manifests they point at, and skips them in recursive deletes. `gcrane_gc` only ever deletes untagged manifests",
+				ElementType:         types.StringType,
+				Optional:            true,
 			},
 			"temporary_directory": schema.StringAttribute{
 				MarkdownDescription: "Temporary directory for Docker config (uses system temp dir by default)",
 				Optional:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"external_account": schema.SingleNestedBlock{
+				MarkdownDescription: "Workload Identity Federation settings used to authenticate to `gcr.io` and `pkg.dev` registries, as an alternative to `credentials`",
+				Attributes: map[string]schema.Attribute{
+					"audience": schema.StringAttribute{
+						MarkdownDescription: "Audience of the workload identity pool provider (`//iam.googleapis.com/projects/.../providers/...`)",
+						Optional:            true,
+					},
+					"subject_token": schema.StringAttribute{
+						MarkdownDescription: "Subject token (for example an OIDC ID token from the CI system)",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"subject_token_file": schema.StringAttribute{
+						MarkdownDescription: "Path to a file containing the subject token, as an alternative to `subject_token`",
+						Optional:            true,
+					},
+					"subject_token_type": schema.StringAttribute{
+						MarkdownDescription: "Type of the subject token (defaults to `urn:ietf:params:oauth:token-type:jwt`)",
+						Optional:            true,
+					},
+					"service_account_impersonation_url": schema.StringAttribute{
+						MarkdownDescription: "URL for impersonating a service account after the token exchange",
+						Optional:            true,
+					},
+					"token_url": schema.StringAttribute{
+						MarkdownDescription: "Security Token Service endpoint (defaults to `https://sts.googleapis.com/v1/token`)",
+						Optional:            true,
+					},
+				},
+			},
+			"http_timeouts": schema.SingleNestedBlock{
+				MarkdownDescription: "Timeouts and connection settings for registry requests. Durations are given like `30s` or `2m`",
+				Attributes: map[string]schema.Attribute{
+					"dial": schema.StringAttribute{
+						MarkdownDescription: "Timeout for establishing connections (defaults to `30s`)",
+						Optional:            true,
+					},
+					"tls_handshake": schema.StringAttribute{
+						MarkdownDescription: "Timeout for TLS handshakes (defaults to `10s`)",
+						Optional:            true,
+					},
+					"response_header": schema.StringAttribute{
+						MarkdownDescription: "Timeout for receiving response headers after a request has been sent, including its body. Keep it long enough for large blob uploads (no timeout by default)",
+						Optional:            true,
+					},
+					"idle_connection": schema.StringAttribute{
+						MarkdownDescription: "How long idle connections are kept open for reuse (defaults to `90s`)",
+						Optional:            true,
+					},
+					"max_idle_connections_per_host": schema.Int64Attribute{
+						MarkdownDescription: "Maximum number of idle connections kept open per registry (defaults to `2`)",
+						Optional:            true,
+					},
+				},
+			},
+			"retries": schema.SingleNestedBlock{
+				MarkdownDescription: "Retry policy for failed registry requests, applied to all resources and data sources. Without this block, only the built-in retries of go-containerregistry apply: up to three attempts on network errors and server errors, for every request except repository listings. This policy applies on top of those built-in retries",
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: "Maximum number of attempts per request, including the first one (defaults to `5`)",
+						Optional:            true,
+					},
+					"min_backoff": schema.StringAttribute{
+						MarkdownDescription: "Delay before the first retry, doubled for every further retry (defaults to `1s`)",
+						Optional:            true,
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: "Maximum delay between retries, also capping `Retry-After` (defaults to `30s`)",
+						Optional:            true,
+					},
+					"retry_on": schema.ListAttribute{
+						MarkdownDescription: "HTTP status codes that are retried, in addition to network errors (defaults to `[429, 500, 502, 503, 504]`)",
+						ElementType:         types.Int64Type,
+						Optional:            true,
+					},
+				},
+			},
+			"registry_auth": schema.ListNestedBlock{
+				MarkdownDescription: "Credentials for a registry, taking precedence over the Docker config",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"address": schema.StringAttribute{
+							MarkdownDescription: "Address of the registry (for example `ghcr.io` or `europe-docker.pkg.dev`)",
+							Required:            true,
+						},
+						"username": schema.StringAttribute{
+							MarkdownDescription: "Username for the registry",
+							Optional:            true,
+						},
+						"password": schema.StringAttribute{
+							MarkdownDescription: "Password for the registry",
+							Optional:            true,
+							Sensitive:           true,
+						},
+						"auth_token": schema.StringAttribute{
+							MarkdownDescription: "Bearer token for the registry, used instead of username and password",
+							Optional:            true,
+							Sensitive:           true,
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
 func (p *GcraneProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data GcraneProviderModel
 
+	// Credentials (like docker_config) may depend on resources that have not
+	// been created yet. Defer everything using this provider until they are
+	// known instead of configuring it without them. This is checked before
+	// reading the configuration, which fails on unknown blocks like
+	// registry_auth.
+	if !req.Config.Raw.IsFullyKnown() {
+		if req.ClientCapabilities.DeferralAllowed {
+			tflog.Debug(ctx, "Provider configuration contains unknown values, deferring")
+			resp.Deferred = &provider.Deferred{
+				Reason: provider.DeferredReasonProviderConfigUnknown,
+			}
+			return
+		}
+		tflog.Warn(ctx, "Provider configuration contains unknown values, which are ignored until they are known")
+	}
+
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	dockerConfig := data.DockerConfig.ValueString()
+	if data.DockerConfigPath.ValueString() != "" {
+		if dockerConfig != "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("docker_config_file"),
+				"Conflicting Docker config",
+				"Only one of docker_config and docker_config_file can be set.",
+			)
+			return
+		}
+		contents, err := os.ReadFile(data.DockerConfigPath.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("docker_config_file"),
+				"Unable to read Docker config file",
+				fmt.Sprintf("Unable to read Docker config file %s: %s", data.DockerConfigPath.ValueString(), err.Error()),
+			)
+			return
+		}
+		dockerConfig = string(contents)
+	}
+
+	registryAuth := staticKeychain{}
+	for i, auth := range data.RegistryAuth {
+		key, err := registryKey(auth.Address.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("registry_auth").AtListIndex(i).AtName("address"),
+				"Invalid registry address",
+				err.Error(),
+			)
+			return
+		}
+		registryAuth[key] = authn.AuthConfig{
+			Username:      auth.Username.ValueString(),
+			Password:      auth.Password.ValueString(),
+			RegistryToken: auth.AuthToken.ValueString(),
+		}
+	}
+
+	if _, ok := registryAuth["ghcr.io"]; !ok && data.GithubToken.ValueString() != "" {
+		// GitHub Container Registry accepts any username with a token.
+		registryAuth["ghcr.io"] = authn.AuthConfig{
+			Username: "token",
+			Password: data.GithubToken.ValueString(),
+		}
+	}
+
+	helpers := map[string]string{}
+	resp.Diagnostics.Append(data.CredentialHelpers.ElementsAs(ctx, &helpers, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	credentialHelpers := credentialHelperKeychain{}
+	for address, helper := range helpers {
+		key, err := registryKey(address)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("credential_helpers").AtMapKey(address),
+				"Invalid registry address",
+				err.Error(),
+			)
+			return
+		}
+		credentialHelpers[key] = helper
+	}
+
+	// Registries with explicitly configured credentials, used by default when
+	// rendering Docker configs.
+	var registries []string
+	for key := range registryAuth {
+		registries = append(registries, key)
+	}
+	for key := range credentialHelpers {
+		if _, ok := registryAuth[key]; !ok {
+			registries = append(registries, key)
+		}
+	}
+	sort.Strings(registries)
+
+	var googleKeychains []authn.Keychain
+	googleCredentials := 0
+	for _, set := range []bool{data.AccessToken.ValueString() != "", data.Credentials.ValueString() != "", data.ExternalAccount != nil} {
+		if set {
+			googleCredentials++
+		}
+	}
+	if googleCredentials > 1 {
+		resp.Diagnostics.AddError(
+			"Conflicting Google credentials",
+			"Only one of access_token, credentials and external_account can be set.",
+		)
+		return
+	}
+	var googleSource googleTokenSource = defaultTokenSource
+	if data.AccessToken.ValueString() != "" {
+		googleSource = accessTokenSource(data.AccessToken.ValueString())
+		googleKeychains = append(googleKeychains, accessTokenKeychain(data.AccessToken.ValueString()))
+	} else if data.ExternalAccount != nil || data.Credentials.ValueString() != "" {
+		var err error
+		attribute, summary := path.Root("credentials"), "Invalid credentials"
+		if data.ExternalAccount != nil {
+			attribute, summary = path.Root("external_account"), "Invalid external account configuration"
+			googleSource, err = externalAccountTokenSource(*data.ExternalAccount)
+		} else {
+			googleSource, err = credentialsTokenSource(data.Credentials.ValueString())
+		}
+		var kc authn.Keychain
+		if err == nil {
+			kc, err = tokenSourceKeychain(googleSource)
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(attribute, summary, err.Error())
+			return
+		}
+		googleKeychains = append(googleKeychains, kc)
+	}
+	googleKeychains = append(googleKeychains, google.Keychain)
+
+	keychainSources := map[string][]authn.Keychain{
+		"registry_auth":      {registryAuth},
+		"credential_helpers": {credentialHelpers},
+		"google":             googleKeychains,
+		"docker_config":      {authn.DefaultKeychain},
+		"ecr":                {ecrKeychain},
+	}
+	keychainOrder := defaultKeychainOrder
+	if !data.Keychains.IsNull() {
+		keychainOrder = nil
+		resp.Diagnostics.Append(data.Keychains.ElementsAs(ctx, &keychainOrder, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	var keychains []authn.Keychain
+	seen := map[string]bool{}
+	for i, source := range keychainOrder {
+		if seen[source] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("keychains").AtListIndex(i),
+				"Duplicate keychain",
+				fmt.Sprintf("Keychain %q is listed more than once.", source),
+			)
+			return
+		}
+		seen[source] = true
+		if source == "anonymous" {
+			break
+		}
+		kcs, ok := keychainSources[source]
+		if !ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("keychains").AtListIndex(i),
+				"Unknown keychain",
+				fmt.Sprintf("Unknown keychain %q, expected one of: registry_auth, credential_helpers, google, docker_config, ecr, anonymous.", source),
+			)
+			return
+		}
+		keychains = append(keychains, kcs...)
+	}
+
+	defaultJobs := runtime.GOMAXPROCS(0)
+	if !data.DefaultJobs.IsNull() {
+		defaultJobs = int(data.DefaultJobs.ValueInt64())
+		if defaultJobs < 1 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("default_jobs"),
+				"Invalid default_jobs",
+				"default_jobs must be one or greater.",
+			)
+			return
+		}
+	}
+
+	var defaultPlatform *v1.Platform
+	if !data.DefaultPlatform.IsNull() {
+		var err error
+		defaultPlatform, err = v1.ParsePlatform(data.DefaultPlatform.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("default_platform"),
+				"Invalid default_platform",
+				fmt.Sprintf("Unable to parse platform %q: %s", data.DefaultPlatform.ValueString(), err),
+			)
+			return
+		}
+	}
+
+	var allowedDestinations, deniedDestinations []string
+	resp.Diagnostics.Append(data.AllowedDestinations.ElementsAs(ctx, &allowedDestinations, false)...)
+	resp.Diagnostics.Append(data.DeniedDestinations.ElementsAs(ctx, &deniedDestinations, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	destinations, err := newDestinationPolicy(allowedDestinations, deniedDestinations)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid destination patterns",
+			err.Error(),
+		)
+		return
+	}
+
+	var protectTags []string
+	resp.Diagnostics.Append(data.ProtectTags.ElementsAs(ctx, &protectTags, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	protectedTags, err := newTagProtection(protectTags)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("protect_tags"),
+			"Invalid protect_tags",
+			err.Error(),
+		)
+		return
+	}
+
+	mirrors := map[string]string{}
+	var configuredMirrors map[string]string
+	resp.Diagnostics.Append(data.RegistryMirrors.ElementsAs(ctx, &configuredMirrors, false)...)
+	for registry, mirror := range configuredMirrors {
+		key, err := registryKey(registry)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("registry_mirrors").AtMapKey(registry),
+				"Invalid registry",
+				err.Error(),
+			)
+			continue
+		}
+		mirrors[key] = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(mirror, "https://"), "http://"), "/")
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var audit *auditLog
+	if data.AuditLogPath.ValueString() != "" {
+		audit = &auditLog{path: data.AuditLogPath.ValueString()}
+	}
+
+	var layers *layerCache
+	if data.CacheDir.ValueString() != "" {
+		var err error
+		layers, err = newLayerCache(data.CacheDir.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("cache_dir"), "Invalid cache_dir", err.Error())
+			return
+		}
+	}
+
+	progressInterval := defaultProgressInterval
+	parseDurations(path.Empty(), []durationAttribute{
+		{"progress_interval", data.ProgressInterval, &progressInterval},
+	}, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tracer, err := newTracer(ctx, data.TracingEndpoint.ValueString(), p.version)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("tracing_endpoint"), "Invalid tracing_endpoint", err.Error())
+		return
+	}
+
+	transportCfg := transportConfig{Failures: &failureLog{}, Tracer: tracer}
+	resp.Diagnostics.Append(data.CACertificates.ElementsAs(ctx, &transportCfg.CACertificates, false)...)
+	resp.Diagnostics.Append(data.InsecureSkipVerify.ElementsAs(ctx, &transportCfg.InsecureSkipVerify, false)...)
+	resp.Diagnostics.Append(data.PlainHTTPRegistries.ElementsAs(ctx, &transportCfg.PlainHTTP, false)...)
+	transportCfg.Proxy.HTTPProxy = data.HTTPProxy.ValueString()
+	transportCfg.Proxy.HTTPSProxy = data.HTTPSProxy.ValueString()
+	transportCfg.Proxy.NoProxy = data.NoProxy.ValueString()
+	transportCfg.UserAgent = data.UserAgent.ValueString()
+	transportCfg.Debug = data.DebugHTTP.ValueBool()
+	transportCfg.RequestsPerSecond = data.RequestsPerSecond.ValueFloat64()
+	if transportCfg.RequestsPerSecond < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Invalid requests_per_second",
+			"requests_per_second must not be negative.",
+		)
+	}
+	transportCfg.MaxBandwidthMbps = data.MaxBandwidthMbps.ValueFloat64()
+	if transportCfg.MaxBandwidthMbps < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_bandwidth_mbps"),
+			"Invalid max_bandwidth_mbps",
+			"max_bandwidth_mbps must not be negative.",
+		)
+	}
+	if data.HTTPTimeouts != nil {
+		transportCfg.Timeouts = timeoutsFromModel(*data.HTTPTimeouts, &resp.Diagnostics)
+	}
+	if data.Retries != nil {
+		transportCfg.Retry = retryPolicyFromModel(ctx, *data.Retries, &resp.Diagnostics)
+	}
+	resp.Diagnostics.Append(data.Headers.ElementsAs(ctx, &transportCfg.Headers, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	transport, err := newTransport(transportCfg)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to configure registry transport",
+			err.Error(),
+		)
+		return
+	}
+
 	providerData := GcraneData{
-		DockerConfigFile: "",
-		DockerConfig:     data.DockerConfig.ValueString(),
-		OriginalEnv:      os.Getenv("DOCKER_CONFIG"),
+		Keychain:          authn.NewMultiKeychain(keychains...),
+		Transport:         transport,
+		DefaultJobs:       defaultJobs,
+		DefaultPlatform:   defaultPlatform,
+		ReadOnly:          data.ReadOnly.ValueBool(),
+		Destinations:      destinations,
+		ProtectedTags:     protectedTags,
+		AuditLog:          audit,
+		Tracer:            tracer,
+		ProgressInterval:  progressInterval,
+		Mirrors:           mirrors,
+		GoogleTokenSource: googleSource,
+		BillingProject:    data.BillingProject.ValueString(),
+		Registries:        registries,
+		Failures:          transportCfg.Failures,
+		LayerCache:        layers,
+		DockerConfigFile:  "",
+		DockerConfig:      dockerConfig,
+		OriginalEnv:       os.Getenv("DOCKER_CONFIG"),
 		Setup: func(ctx context.Context, data interface{}) error {
-			gcraneData, ok := data.(GcraneData)
+			gcraneData, ok := data.(*GcraneData)
 			if !ok {
 				return fmt.Errorf("received unexpected data structure")
 			}
+			gcraneData.ConfigLock.Lock()
+			defer gcraneData.ConfigLock.Unlock()
+
 			gcraneData.Counter.Add(1)
 			if gcraneData.DockerConfig != "" && gcraneData.DockerConfigFile != "" && !gcraneData.DockerIsConfigured.Load() {
 				gcraneData.DockerIsConfigured.Store(true)
@@ -134,30 +910,27 @@ func (p *GcraneProvider) Configure(ctx context.Context, req provider.ConfigureRe
 					return fmt.Errorf("unable to close temporary file for Docker config %s: %s", gcraneData.DockerConfigFile, err.Error())
 				}
 
-				gcraneData.ConfigLock.Lock()
 				os.Setenv("DOCKER_CONFIG", dockerConfigDir)
 				tflog.Trace(ctx, "Using temporary Docker config", map[string]interface{}{
 					"directory": dockerConfigDir,
 					"file":      gcraneData.DockerConfigFile,
 				})
-				gcraneData.ConfigLock.Unlock()
 			}
 			return nil
 		},
 		// Terrible emulation of provider teardown, see: https://github.com/hashicorp/terraform-plugin-sdk/issues/63
 		Cleanup: func(ctx context.Context, data interface{}) error {
-			gcraneData, ok := data.(GcraneData)
+			gcraneData, ok := data.(*GcraneData)
 			if !ok {
 				return fmt.Errorf("received unexpected data structure")
 			}
+			gcraneData.ConfigLock.Lock()
+			defer gcraneData.ConfigLock.Unlock()
 
-			gcraneData.Counter.Add(-1)
-			if gcraneData.Counter.Load() == 0 {
+			if gcraneData.Counter.Add(-1) == 0 {
 				if gcraneData.DockerConfig != "" && gcraneData.DockerConfigFile != "" && gcraneData.DockerIsConfigured.Load() {
 					gcraneData.DockerIsConfigured.Store(false)
 
-					gcraneData.ConfigLock.Lock()
-					defer gcraneData.ConfigLock.Unlock()
 					tflog.Trace(ctx, "Cleaning up temporary Docker config", map[string]interface{}{
 						"file": gcraneData.DockerConfigFile,
 					})
@@ -205,26 +978,64 @@ func (p *GcraneProvider) Configure(ctx context.Context, req provider.ConfigureRe
 
 	resp.DataSourceData = &providerData
 	resp.ResourceData = &providerData
+	resp.EphemeralResourceData = &providerData
+	resp.ActionData = &providerData
+	resp.ListResourceData = &providerData
 }
 
 func (p *GcraneProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewCopyResource,
+		NewCheckResource,
 	}
 }
 
 func (p *GcraneProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
-	return []func() ephemeral.EphemeralResource{}
+	return []func() ephemeral.EphemeralResource{
+		NewAuthTokenEphemeralResource,
+		NewDockerConfigEphemeralResource,
+	}
+}
+
+func (p *GcraneProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewCopyAction,
+		NewDeleteAction,
+		NewGcAction,
+	}
+}
+
+func (p *GcraneProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewCopyListResource,
+	}
 }
 
 func (p *GcraneProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewGcraneListDataSource,
+		NewGcraneImagePullSecretDataSource,
+		NewGcraneImageSizeDataSource,
+		NewGcraneImageLabelDataSource,
+		NewGcraneImageConfigDataSource,
+		NewGcraneBaseImageDataSource,
+		NewGcraneProvenanceDataSource,
+		NewGcraneArtifactRegistryRepositoriesDataSource,
+		NewGcraneGcrMigrationPlanDataSource,
 	}
 }
 
 func (p *GcraneProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewParseReferenceFunction,
+		NewPinFunction,
+		NewArtifactRegistryRefFunction,
+		NewGcrToArFunction,
+		NewIsDigestReferenceFunction,
+		NewNormalizeReferenceFunction,
+		NewSemverLatestFunction,
+		NewJoinReferenceFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -234,3 +1045,82 @@ func New(version string) func() provider.Provider {
 		}
 	}
 }
+
+// retryPolicyFromModel builds the retry policy from the retries block,
+// using the defaults for unset attributes.
+func retryPolicyFromModel(ctx context.Context, model GcraneProviderRetriesModel, diags *diag.Diagnostics) *retryPolicy {
+	policy := defaultRetryPolicy
+	if !model.MaxAttempts.IsNull() {
+		policy.MaxAttempts = int(model.MaxAttempts.ValueInt64())
+		if policy.MaxAttempts < 1 {
+			diags.AddAttributeError(
+				path.Root("retries").AtName("max_attempts"),
+				"Invalid max_attempts",
+				"max_attempts must be at least 1.",
+			)
+		}
+	}
+	parseDurations(path.Root("retries"), []durationAttribute{
+		{"min_backoff", model.MinBackoff, &policy.MinBackoff},
+		{"max_backoff", model.MaxBackoff, &policy.MaxBackoff},
+	}, diags)
+	if !model.RetryOn.IsNull() {
+		var codes []int64
+		diags.Append(model.RetryOn.ElementsAs(ctx, &codes, false)...)
+		policy.RetryOn = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			policy.RetryOn[int(code)] = true
+		}
+	}
+	return &policy
+}
+
+// timeoutsFromModel builds the transport timeouts from the http_timeouts
+// block. Unset attributes keep the Go defaults.
+func timeoutsFromModel(model GcraneProviderHTTPTimeoutsModel, diags *diag.Diagnostics) transportTimeouts {
+	timeouts := transportTimeouts{}
+	parseDurations(path.Root("http_timeouts"), []durationAttribute{
+		{"dial", model.Dial, &timeouts.Dial},
+		{"tls_handshake", model.TLSHandshake, &timeouts.TLSHandshake},
+		{"response_header", model.ResponseHeader, &timeouts.ResponseHeader},
+		{"idle_connection", model.IdleConnection, &timeouts.IdleConnection},
+	}, diags)
+	if !model.MaxIdleConnectionsPerHost.IsNull() {
+		timeouts.MaxIdleConnsPerHost = int(model.MaxIdleConnectionsPerHost.ValueInt64())
+		if timeouts.MaxIdleConnsPerHost < 1 {
+			diags.AddAttributeError(
+				path.Root("http_timeouts").AtName("max_idle_connections_per_host"),
+				"Invalid max_idle_connections_per_host",
+				"max_idle_connections_per_host must be at least 1.",
+			)
+		}
+	}
+	return timeouts
+}
+
+// durationAttribute is a duration attribute of a nested block.
+type durationAttribute struct {
+	name  string
+	value types.String
+	out   *time.Duration
+}
+
+// parseDurations parses the set duration attributes of the block at p into
+// their targets.
+func parseDurations(p path.Path, attributes []durationAttribute, diags *diag.Diagnostics) {
+	for _, attribute := range attributes {
+		if attribute.value.IsNull() {
+			continue
+		}
+		d, err := time.ParseDuration(attribute.value.ValueString())
+		if err != nil || d < 0 {
+			diags.AddAttributeError(
+				p.AtName(attribute.name),
+				"Invalid duration",
+				fmt.Sprintf("Expected a duration like \"1s\" or \"500ms\", got %q.", attribute.value.ValueString()),
+			)
+			continue
+		}
+		*attribute.out = d
+	}
+}
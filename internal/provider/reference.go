@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// dockerHubRegistry is the registry name Docker uses for Docker Hub.
+const dockerHubRegistry = "docker.io"
+
+// referenceParts are the components of an image reference.
+type referenceParts struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// parseReference splits an image reference into its components, applying
+// the same defaults as Docker: Docker Hub for references without a
+// registry, library/ for official images and the latest tag for references
+// with neither a tag nor a digest.
+func parseReference(reference string) (referenceParts, error) {
+	parts := referenceParts{}
+
+	base, digest, hasDigest := strings.Cut(reference, "@")
+	if hasDigest {
+		if _, err := v1.NewHash(digest); err != nil {
+			return parts, fmt.Errorf("invalid digest %q in reference %q: %w", digest, reference, err)
+		}
+		parts.Digest = digest
+	}
+
+	tag, err := name.NewTag(base, name.WeakValidation)
+	if err != nil {
+		return parts, fmt.Errorf("invalid reference %q: %w", reference, err)
+	}
+	parts.Registry = tag.RegistryStr()
+	if parts.Registry == name.DefaultRegistry {
+		parts.Registry = dockerHubRegistry
+	}
+	parts.Repository = tag.RepositoryStr()
+
+	explicitTag := strings.LastIndex(base, ":") > strings.LastIndex(base, "/")
+	if explicitTag || !hasDigest {
+		parts.Tag = tag.TagStr()
+	}
+	return parts, nil
+}
+
+// String joins the components back into a reference.
+func (p referenceParts) String() string {
+	reference := p.Registry + "/" + p.Repository
+	if p.Tag != "" {
+		reference += ":" + p.Tag
+	}
+	if p.Digest != "" {
+		reference += "@" + p.Digest
+	}
+	return reference
+}
+
+// normalizeReference normalizes a reference the way Docker does, lower
+// casing the registry host and filling in the defaults of parseReference.
+func normalizeReference(reference string) (string, error) {
+	// Like Docker, the first component is a registry host when it looks
+	// like a host name.
+	if host, rest, ok := strings.Cut(reference, "/"); ok {
+		if strings.ContainsAny(host, ".:") || strings.EqualFold(host, "localhost") {
+			reference = strings.ToLower(host) + "/" + rest
+		}
+	}
+	parts, err := parseReference(reference)
+	if err != nil {
+		return "", err
+	}
+	return parts.String(), nil
+}
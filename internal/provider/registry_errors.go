@@ -0,0 +1,258 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// requestIDHeaders are the response headers registries and the services in
+// front of them use to identify requests for support.
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Amzn-Requestid",
+	"X-Amz-Request-Id",
+	"X-Amz-Cf-Id",
+	"X-Cloud-Trace-Context",
+	"X-Guploader-Uploadid",
+	"X-Github-Request-Id",
+	"Cf-Ray",
+}
+
+// failedResponse is what is kept of a failed registry response.
+type failedResponse struct {
+	Status     string
+	RequestIDs []string
+	RetryAfter string
+}
+
+// failureLogSize is the number of failed responses kept.
+const failureLogSize = 64
+
+// failureLog keeps the most recent failed registry responses by request, as
+// registry errors do not include the response headers.
+type failureLog struct {
+	mu      sync.Mutex
+	entries map[string]failedResponse
+	order   []string
+}
+
+// failureKey identifies a request, leaving out the query which may hold
+// credentials.
+func failureKey(req *http.Request) string {
+	return req.Method + " " + req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+}
+
+func (l *failureLog) record(resp *http.Response) {
+	failure := failedResponse{
+		Status:     resp.Status,
+		RetryAfter: resp.Header.Get("Retry-After"),
+	}
+	for _, header := range requestIDHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			failure.RequestIDs = append(failure.RequestIDs, header+": "+value)
+		}
+	}
+
+	key := failureKey(resp.Request)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.entries == nil {
+		l.entries = map[string]failedResponse{}
+	}
+	if _, ok := l.entries[key]; !ok {
+		l.order = append(l.order, key)
+	}
+	l.entries[key] = failure
+	if len(l.order) > failureLogSize {
+		delete(l.entries, l.order[0])
+		l.order = l.order[1:]
+	}
+}
+
+// lookup returns the last failed response to a request like req. Looking
+// up in a nil log finds nothing.
+func (l *failureLog) lookup(req *http.Request) (failedResponse, bool) {
+	if l == nil || req == nil {
+		return failedResponse{}, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	failure, ok := l.entries[failureKey(req)]
+	return failure, ok
+}
+
+// failureTransport records failed responses in a failure log.
+type failureTransport struct {
+	inner http.RoundTripper
+	log   *failureLog
+}
+
+func (t failureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.inner.RoundTrip(req)
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		if resp.Request == nil {
+			resp.Request = req
+		}
+		t.log.record(resp)
+	}
+	return resp, err
+}
+
+// registryErrorDetail describes a registry error for a diagnostic: the
+// failed request and the error codes returned by the registry, the status,
+// request IDs and Retry-After of the response when found in failures,
+// followed by advice for the common error codes. Other errors are returned
+// as is.
+func registryErrorDetail(err error, failures *failureLog) string {
+	var terr *transport.Error
+	if !errors.As(err, &terr) {
+		return err.Error()
+	}
+
+	var detail strings.Builder
+	if terr.Request != nil {
+		fmt.Fprintf(&detail, "%s %s://%s%s: ", terr.Request.Method, terr.Request.URL.Scheme, terr.Request.URL.Host, terr.Request.URL.Path)
+	}
+	if len(terr.Errors) == 0 {
+		fmt.Fprintf(&detail, "unexpected status code %d %s", terr.StatusCode, http.StatusText(terr.StatusCode))
+	}
+	for i, d := range terr.Errors {
+		if i > 0 {
+			detail.WriteString("; ")
+		}
+		detail.WriteString(string(d.Code))
+		if d.Message != "" {
+			detail.WriteString(": " + d.Message)
+		}
+	}
+
+	if failure, ok := failures.lookup(terr.Request); ok {
+		detail.WriteString("\n\nStatus: " + failure.Status)
+		for _, id := range failure.RequestIDs {
+			detail.WriteString("\n" + id)
+		}
+		if failure.RetryAfter != "" {
+			detail.WriteString("\nRetry-After: " + failure.RetryAfter)
+		}
+	}
+
+	if hint := registryErrorHint(terr); hint != "" {
+		detail.WriteString("\n\n" + hint)
+	}
+	return detail.String()
+}
+
+// isManifestUnknown reports whether err is a registry error for a missing
+// manifest or repository: a MANIFEST_UNKNOWN or NAME_UNKNOWN error code, or
+// a not found status without error codes, like the responses to HEAD
+// requests.
+func isManifestUnknown(err error) bool {
+	var terr *transport.Error
+	if !errors.As(err, &terr) {
+		return false
+	}
+	for _, d := range terr.Errors {
+		if d.Code == transport.ManifestUnknownErrorCode || d.Code == transport.NameUnknownErrorCode {
+			return true
+		}
+	}
+	return len(terr.Errors) == 0 && terr.StatusCode == http.StatusNotFound
+}
+
+// registryErrorHint returns advice for the error codes (or, without codes,
+// the status) of a registry error.
+func registryErrorHint(terr *transport.Error) string {
+	host, repository, write := registryErrorTarget(terr.Request)
+	target := "the repository"
+	if repository != "" {
+		target = host + "/" + repository
+	} else if host != "" {
+		target = host
+	}
+	registry := "the registry"
+	if host != "" {
+		registry = host
+	}
+
+	code := transport.ErrorCode("")
+	if len(terr.Errors) > 0 {
+		code = terr.Errors[0].Code
+	} else {
+		switch terr.StatusCode {
+		case http.StatusUnauthorized:
+			code = transport.UnauthorizedErrorCode
+		case http.StatusForbidden:
+			code = transport.DeniedErrorCode
+		case http.StatusTooManyRequests:
+			code = transport.TooManyRequestsErrorCode
+		}
+	}
+
+	switch code {
+	case transport.UnauthorizedErrorCode:
+		return fmt.Sprintf("The registry did not accept the credentials for %s. Check that credentials are configured for the registry (with registry_auth, docker_config, credential_helpers or Google credentials) and have not expired.", target)
+	case transport.DeniedErrorCode:
+		if isGoogleRegistry(host) {
+			role := "roles/artifactregistry.reader"
+			if write {
+				role = "roles/artifactregistry.writer"
+			}
+			return fmt.Sprintf("The credentials do not have access to %s. Check that the principal has %s on the repository (or its project).", target, role)
+		}
+		return fmt.Sprintf("The credentials do not have access to %s.", target)
+	case transport.ManifestUnknownErrorCode:
+		return fmt.Sprintf("Image not found in %s: check the tag or digest.", target)
+	case transport.NameUnknownErrorCode:
+		return fmt.Sprintf("Repository %s not found: check the repository name, and that the repository has been created.", target)
+	case transport.TooManyRequestsErrorCode:
+		return fmt.Sprintf("Requests are rate limited by %s. Configure retries or requests_per_second in the provider, or authenticate to get a higher quota.", registry)
+	}
+	return ""
+}
+
+// registryErrorTarget returns the host and repository of a failed request,
+// and whether it was a write. For token requests the repository is taken
+// from the requested scope, and the host is the token server.
+func registryErrorTarget(req *http.Request) (host, repository string, write bool) {
+	if req == nil {
+		return "", "", false
+	}
+	host = req.URL.Host
+	write = req.Method != http.MethodGet && req.Method != http.MethodHead
+
+	if rest, ok := strings.CutPrefix(req.URL.Path, "/v2/"); ok {
+		for _, sep := range []string{"/manifests/", "/blobs/", "/tags/"} {
+			if i := strings.Index(rest, sep); i >= 0 {
+				return host, rest[:i], write
+			}
+		}
+		return host, "", write
+	}
+
+	// repository:<name>:<actions>
+	for _, scope := range req.URL.Query()["scope"] {
+		parts := strings.Split(scope, ":")
+		if len(parts) == 3 && parts[0] == "repository" {
+			return host, parts[1], strings.Contains(parts[2], "push")
+		}
+	}
+	return host, "", write
+}
@@ -0,0 +1,354 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// testRegistry is an in-memory registry served in process, so acceptance
+// tests do not need registry credentials. Registries on 127.0.0.1 are
+// accessed over plain HTTP without any provider configuration.
+type testRegistry struct {
+	// Host is the address of the registry, like 127.0.0.1:12345.
+	Host string
+	// auth is used by the fixture builder to push to the registry.
+	auth authn.Authenticator
+	// listings are served by registries from newTestListingRegistry.
+	listings *testListings
+}
+
+// newTestRegistry starts a registry that is shut down when the test ends.
+func newTestRegistry(t *testing.T) *testRegistry {
+	t.Helper()
+
+	return startTestRegistry(t, registry.New(registry.Logger(log.New(io.Discard, "", 0))), authn.Anonymous)
+}
+
+// newTestRegistryWithAuth starts a registry that requires basic
+// authentication with username and password.
+func newTestRegistryWithAuth(t *testing.T, username, password string) *testRegistry {
+	t.Helper()
+
+	handler := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	authenticated := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if u, p, ok := req.BasicAuth(); !ok || u != username || p != password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+	return startTestRegistry(t, authenticated, &authn.Basic{Username: username, Password: password})
+}
+
+// newTestListingRegistry starts a registry that lists repositories like
+// gcr.io and pkg.dev: the tags/list responses of the repositories set with
+// SetListing include their manifests, with tags and timestamps, and their
+// child repositories. Listings are split into pages of pageSize manifests
+// (0 for a single page).
+func newTestListingRegistry(t *testing.T, pageSize int) *testRegistry {
+	t.Helper()
+
+	listings := &testListings{pageSize: pageSize, tags: make(map[string]*google.Tags, 0)}
+	handler := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	listing := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		repository, ok := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/v2/"), "/tags/list")
+		if ok && listings.serve(w, req, repository) {
+			return
+		}
+		handler.ServeHTTP(w, req)
+	})
+	r := startTestRegistry(t, listing, authn.Anonymous)
+	r.listings = listings
+	return r
+}
+
+// SetListing sets the listing of repository in a registry from
+// newTestListingRegistry. Child repositories are only listed when they
+// have a listing of their own.
+func (r *testRegistry) SetListing(repository string, tags *google.Tags) {
+	r.listings.lock.Lock()
+	defer r.listings.lock.Unlock()
+	r.listings.tags[repository] = tags
+}
+
+// testListings are the Google-style listings of a test registry.
+type testListings struct {
+	lock     sync.Mutex
+	pageSize int
+	tags     map[string]*google.Tags
+}
+
+// serve writes the page of the listing of repository requested by req,
+// and reports whether repository has a listing.
+func (l *testListings) serve(w http.ResponseWriter, req *http.Request, repository string) bool {
+	l.lock.Lock()
+	tags, ok := l.tags[repository]
+	l.lock.Unlock()
+	if !ok {
+		return false
+	}
+
+	digests := make([]string, 0, len(tags.Manifests))
+	for digest := range tags.Manifests {
+		digests = append(digests, digest)
+	}
+	sort.Strings(digests)
+	page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+	if l.pageSize > 0 {
+		start := min(page*l.pageSize, len(digests))
+		end := min(start+l.pageSize, len(digests))
+		if end < len(digests) {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/%s/tags/list?page=%d>; rel="next"`, repository, page+1))
+		}
+		digests = digests[start:end]
+	}
+
+	// Tags and children are returned with the first page.
+	body := google.Tags{Name: repository, Manifests: make(map[string]google.ManifestInfo, len(digests))}
+	if page == 0 {
+		body.Tags, body.Children = tags.Tags, tags.Children
+	}
+	for _, digest := range digests {
+		body.Manifests[digest] = tags.Manifests[digest]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return true
+}
+
+// startTestRegistry serves handler until the test ends.
+func startTestRegistry(t *testing.T, handler http.Handler, auth authn.Authenticator) *testRegistry {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testRegistry{Host: u.Host, auth: auth}
+}
+
+// Ref returns reference (a repository, optionally with a tag or digest) in
+// the registry.
+func (r *testRegistry) Ref(reference string) string {
+	return r.Host + "/" + reference
+}
+
+// testImage describes a synthetic image built by the fixture builder.
+type testImage struct {
+	// Platform of the image, like linux/amd64 (defaults to linux/amd64).
+	Platform string
+	// Layers is the number of random layers (defaults to one).
+	Layers int
+	// Labels are set in the image configuration.
+	Labels map[string]string
+	// Config is applied to the image configuration after Labels.
+	Config func(*v1.Config)
+	// Base is the image the random layers are added on top of.
+	Base v1.Image
+	// Annotations are set in the image manifest.
+	Annotations map[string]string
+}
+
+// buildTestImage builds an image with random layers from spec.
+func buildTestImage(t *testing.T, spec testImage) v1.Image {
+	t.Helper()
+
+	layers := spec.Layers
+	if layers == 0 {
+		layers = 1
+	}
+	img, err := random.Image(1024, int64(layers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Base != nil {
+		added, err := img.Layers()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img, err = mutate.AppendLayers(spec.Base, added...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = cfg.DeepCopy()
+	platform := testPlatform(t, spec.Platform)
+	cfg.OS = platform.OS
+	cfg.Architecture = platform.Architecture
+	cfg.Variant = platform.Variant
+	cfg.Config.Labels = spec.Labels
+	if spec.Config != nil {
+		spec.Config(&cfg.Config)
+	}
+	img, err = mutate.ConfigFile(img, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Annotations != nil {
+		img = mutate.Annotations(img, spec.Annotations).(v1.Image)
+	}
+	return img
+}
+
+// testPlatform parses platform, defaulting to linux/amd64.
+func testPlatform(t *testing.T, platform string) *v1.Platform {
+	t.Helper()
+
+	if platform == "" {
+		platform = "linux/amd64"
+	}
+	p, err := v1.ParsePlatform(platform)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// SeedRandomImage pushes a random single layer image to reference in the
+// registry and returns its digest.
+func (r *testRegistry) SeedRandomImage(t *testing.T, reference string) string {
+	t.Helper()
+
+	return r.PushImage(t, reference, testImage{})
+}
+
+// PushImage builds an image from spec, pushes it to reference in the
+// registry and returns its digest.
+func (r *testRegistry) PushImage(t *testing.T, reference string, spec testImage) string {
+	t.Helper()
+
+	ref, err := name.ParseReference(r.Ref(reference))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img := buildTestImage(t, spec)
+	if err := remote.Write(ref, img, remote.WithAuth(r.auth)); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return digest.String()
+}
+
+// PushIndex builds an image index with an image per spec, pushes it to
+// reference in the registry and returns the digest of the index and the
+// digests of the images by platform.
+func (r *testRegistry) PushIndex(t *testing.T, reference string, specs ...testImage) (string, map[string]string) {
+	t.Helper()
+
+	ref, err := name.ParseReference(r.Ref(reference))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var index v1.ImageIndex = empty.Index
+	digests := map[string]string{}
+	for _, spec := range specs {
+		img := buildTestImage(t, spec)
+		platform := testPlatform(t, spec.Platform)
+		index = mutate.AppendManifests(index, mutate.IndexAddendum{
+			Add:        img,
+			Descriptor: v1.Descriptor{Platform: platform},
+		})
+		digest, err := img.Digest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digests[platform.String()] = digest.String()
+	}
+
+	if err := remote.WriteIndex(ref, index, remote.WithAuth(r.auth)); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := index.Digest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return digest.String(), digests
+}
+
+// Image returns the image at reference in the registry.
+func (r *testRegistry) Image(t *testing.T, reference string) v1.Image {
+	t.Helper()
+
+	ref, err := name.ParseReference(r.Ref(reference))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := remote.Image(ref, remote.WithAuth(r.auth))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+// Digest returns the digest of reference in the registry.
+func (r *testRegistry) Digest(reference string) (string, error) {
+	ref, err := name.ParseReference(r.Ref(reference))
+	if err != nil {
+		return "", err
+	}
+	desc, err := remote.Head(ref, remote.WithAuth(r.auth))
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
+}
+
+// Delete deletes reference from the registry.
+func (r *testRegistry) Delete(t *testing.T, reference string) {
+	t.Helper()
+
+	ref, err := name.ParseReference(r.Ref(reference))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Delete(ref, remote.WithAuth(r.auth)); err != nil {
+		t.Fatal(err)
+	}
+}
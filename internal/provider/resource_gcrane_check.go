@@ -0,0 +1,338 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CheckResource{}
+var _ resource.ResourceWithImportState = &CheckResource{}
+var _ resource.ResourceWithModifyPlan = &CheckResource{}
+
+func NewCheckResource() resource.Resource {
+	return &CheckResource{}
+}
+
+// CheckResource defines the resource implementation.
+type CheckResource struct {
+	Client *GcraneData
+}
+
+// CheckResourceModel describes the resource data model.
+type CheckResourceModel struct {
+	Id                  types.String `tfsdk:"id"`
+	Reference           types.String `tfsdk:"reference"`
+	ExpectedDigest      types.String `tfsdk:"expected_digest"`
+	RequiredAnnotations types.Map    `tfsdk:"required_annotations"`
+	Severity            types.String `tfsdk:"severity"`
+	Digest              types.String `tfsdk:"digest"`
+	Passed              types.Bool   `tfsdk:"passed"`
+}
+
+func (r *CheckResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_check"
+}
+
+func (r *CheckResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Asserts that an image exists, and optionally that it has an expected digest or required annotations, every time the resource is created, updated or refreshed, like a registry-focused `check` block. Failed assertions are reported as errors (or warnings, with `severity`) when the resource is created or updated. Refreshes only report them as warnings, so that they do not block planning or destroying, and record them in `passed`: a failed check with the `error` severity is then planned to be checked again, which fails the apply if it still fails. Nothing is written to the registry",
+		Description:         "Asserts that an image exists, and optionally its digest and annotations, on every refresh",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Identifier (the reference)",
+			},
+			"reference": schema.StringAttribute{
+				MarkdownDescription: "Image tag or digest reference that must exist",
+				Required:            true,
+			},
+			"expected_digest": schema.StringAttribute{
+				MarkdownDescription: "Digest the reference must resolve to, for example to detect a tag retargeted outside Terraform",
+				Optional:            true,
+			},
+			"required_annotations": schema.MapAttribute{
+				MarkdownDescription: "Annotations the manifest (or image index) must have, keyed by name. An empty value only requires the annotation to be set",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"severity": schema.StringAttribute{
+				MarkdownDescription: "Report failed assertions as an `error` or a `warning` when the resource is created or updated (defaults to `error`). Refreshes always report them as warnings. Registry errors other than a missing reference are always errors",
+				Optional:            true,
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "Digest the reference resolved to when last checked",
+				Computed:            true,
+			},
+			"passed": schema.BoolAttribute{
+				MarkdownDescription: "Whether all assertions passed when last checked",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *CheckResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*GcraneData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *GcraneData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.Client = client
+}
+
+func (r *CheckResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data CheckResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failures, diags := r.check(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(checkFailures(&data, failures, data.Severity.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CheckResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CheckResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failures, diags := r.check(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Failed assertions only warn while refreshing, so that they do not
+	// block planning or destroying, and the refreshed digest and result are
+	// always saved.
+	resp.Diagnostics.Append(checkFailures(&data, failures, "warning")...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CheckResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data CheckResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	failures, diags := r.check(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(checkFailures(&data, failures, data.Severity.ValueString())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// ModifyPlan plans to check again images that failed a check with the error
+// severity when last refreshed, so that applying fails if they still do.
+func (r *CheckResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is checked again when creating or destroying.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var passed types.Bool
+	var severity types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("passed"), &passed)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("severity"), &severity)...)
+	if resp.Diagnostics.HasError() || passed.IsNull() || passed.ValueBool() || severity.ValueString() == "warning" {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("digest"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("passed"), types.BoolUnknown())...)
+}
+
+func (r *CheckResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+func (r *CheckResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("reference"), req, resp)
+}
+
+// check resolves the reference of data and asserts its expectations,
+// recording the digest and whether they passed in data. The failed
+// assertions are returned, to be reported with checkFailures.
+func (r *CheckResource) check(ctx context.Context, data *CheckResourceModel) (failures []string, diags diag.Diagnostics) {
+	ref, err := name.ParseReference(data.Reference.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("reference"), "Invalid reference", err.Error())
+	}
+	if !data.ExpectedDigest.IsNull() {
+		if _, err := v1.NewHash(data.ExpectedDigest.ValueString()); err != nil {
+			diags.AddAttributeError(path.Root("expected_digest"), "Invalid expected_digest", err.Error())
+		}
+	}
+	switch data.Severity.ValueString() {
+	case "", "error", "warning":
+	default:
+		diags.AddAttributeError(path.Root("severity"), "Invalid severity", fmt.Sprintf("Unknown severity %q, expected one of: error, warning.", data.Severity.ValueString()))
+	}
+	var annotations map[string]string
+	diags.Append(data.RequiredAnnotations.ElementsAs(ctx, &annotations, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	ctx, span := r.Client.Tracer.Start(ctx, "check", attribute.String("gcrane.reference", ref.String()))
+	defer func() { span.End(ctx, diags) }()
+
+	err = r.Client.Setup(ctx, r.Client)
+	if err != nil {
+		diags.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return nil, diags
+	}
+	defer func() {
+		err := r.Client.Cleanup(ctx, r.Client)
+		if err != nil {
+			diags.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	data.Id = data.Reference
+	data.Digest = types.StringNull()
+
+	desc, err := remote.Get(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(r.Client.Keychain), remote.WithTransport(r.Client.Transport))
+	switch {
+	case err != nil && isNotFound(err):
+		failures = append(failures, fmt.Sprintf("%s does not exist", ref))
+	case err != nil:
+		diags.AddError(
+			"Could not check image",
+			fmt.Sprintf("Error when reading %s: %s", ref, registryErrorDetail(err, r.Client.Failures)),
+		)
+		return nil, diags
+	default:
+		data.Digest = types.StringValue(desc.Digest.String())
+		failures, err = checkExpectations(desc, data.ExpectedDigest.ValueString(), annotations)
+		if err != nil {
+			diags.AddError(
+				"Could not check image",
+				fmt.Sprintf("Error when reading the manifest of %s: %s", ref, err.Error()),
+			)
+			return nil, diags
+		}
+	}
+
+	data.Passed = types.BoolValue(len(failures) == 0)
+	return failures, diags
+}
+
+// checkFailures reports the failed assertions of the check of data as errors,
+// or as warnings with the warning severity.
+func checkFailures(data *CheckResourceModel, failures []string, severity string) (diags diag.Diagnostics) {
+	if len(failures) == 0 {
+		return diags
+	}
+	detail := fmt.Sprintf("Check of %s failed:\n\n- %s", data.Reference.ValueString(), strings.Join(failures, "\n- "))
+	if severity == "warning" {
+		diags.AddWarning("Image check failed", detail)
+	} else {
+		diags.AddError("Image check failed", detail)
+	}
+	return diags
+}
+
+// checkExpectations returns the expectations desc does not meet: its digest
+// must be digest, if set, and its manifest (or image index) must have the
+// annotations, with the same values unless they are empty.
+func checkExpectations(desc *remote.Descriptor, digest string, annotations map[string]string) ([]string, error) {
+	var failures []string
+	if digest != "" && desc.Digest.String() != digest {
+		failures = append(failures, fmt.Sprintf("digest is %s, expected %s", desc.Digest, digest))
+	}
+	if len(annotations) == 0 {
+		return failures, nil
+	}
+
+	// Image manifests and image indexes both keep annotations at the top level.
+	var manifest struct {
+		Annotations map[string]string `json:"annotations"`
+	}
+	if err := json.Unmarshal(desc.Manifest, &manifest); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value, ok := manifest.Annotations[k]
+		switch {
+		case !ok:
+			failures = append(failures, fmt.Sprintf("annotation %s is not set", k))
+		case annotations[k] != "" && value != annotations[k]:
+			failures = append(failures, fmt.Sprintf("annotation %s is %q, expected %q", k, value, annotations[k]))
+		}
+	}
+	return failures, nil
+}
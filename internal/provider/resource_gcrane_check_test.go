@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccCheckResource(t *testing.T) {
+	registry := newTestRegistry(t)
+	digest := registry.PushImage(t, "app/server:v1", testImage{
+		Annotations: map[string]string{
+			"org.opencontainers.image.revision": "abc123",
+			"org.opencontainers.image.source":   "https://github.com/example/server",
+		},
+	})
+	reference := registry.Ref("app/server:v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckResourceConfig(reference, digest, `"org.opencontainers.image.revision" = "abc123", "org.opencontainers.image.source" = ""`, "error"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_check.image",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digest),
+					),
+					statecheck.ExpectKnownValue(
+						"gcrane_check.image",
+						tfjsonpath.New("passed"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+			// The tag is retargeted outside Terraform: refreshing only records
+			// that the check failed.
+			{
+				PreConfig: func() {
+					registry.SeedRandomImage(t, "app/server:v1")
+				},
+				RefreshState: true,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("gcrane_check.image", "passed", "false"),
+					resource.TestCheckResourceAttrWith("gcrane_check.image", "digest", func(value string) error {
+						if value == digest {
+							return fmt.Errorf("digest was not refreshed")
+						}
+						return nil
+					}),
+				),
+			},
+			// Applying checks again, and fails.
+			{
+				Config: testAccCheckResourceConfig(reference, digest, `"org.opencontainers.image.revision" = "abc123", "org.opencontainers.image.source" = ""`, "error"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gcrane_check.image", plancheck.ResourceActionUpdate),
+						plancheck.ExpectUnknownValue("gcrane_check.image", tfjsonpath.New("passed")),
+					},
+				},
+				ExpectError: regexp.MustCompile(`Image check failed`),
+			},
+			{
+				Config: testAccCheckResourceConfig(reference, digest, `"org.opencontainers.image.revision" = "abc123"`, "warning"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_check.image",
+						tfjsonpath.New("passed"),
+						knownvalue.Bool(false),
+					),
+				},
+			},
+			{
+				Config:      testAccCheckResourceConfig(registry.Ref("app/missing:v1"), digest, "", "error"),
+				ExpectError: regexp.MustCompile(`does not exist`),
+			},
+		},
+	})
+}
+
+func testAccCheckResourceConfig(reference string, digest string, annotations string, severity string) string {
+	return fmt.Sprintf(`
+resource "gcrane_check" "image" {
+  reference            = "%s"
+  expected_digest      = "%s"
+  required_annotations = { %s }
+  severity             = "%s"
+}
+`, reference, digest, annotations, severity)
+}
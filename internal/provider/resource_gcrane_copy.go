@@ -17,21 +17,36 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/gcrane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &CopyResource{}
 var _ resource.ResourceWithImportState = &CopyResource{}
+var _ resource.ResourceWithModifyPlan = &CopyResource{}
+var _ resource.ResourceWithIdentity = &CopyResource{}
+var _ resource.ResourceWithUpgradeState = &CopyResource{}
 
 func NewCopyResource() resource.Resource {
 	return &CopyResource{}
@@ -40,14 +55,56 @@ func NewCopyResource() resource.Resource {
 // CopyResource defines the resource implementation.
 type CopyResource struct {
 	Client *GcraneData
+	// keychain overrides the provider keychain for a single copy.
+	keychain authn.Keychain
 }
 
 // CopyResourceModel describes the resource data model.
 type CopyResourceModel struct {
-	Recursive   types.Bool   `tfsdk:"recursive"`
-	Source      types.String `tfsdk:"source"`
+	Recursive           types.Bool                      `tfsdk:"recursive"`
+	Jobs                types.Int64                     `tfsdk:"jobs"`
+	MaxBandwidthMbps    types.Float64                   `tfsdk:"max_bandwidth_mbps"`
+	DryRun              types.Bool                      `tfsdk:"dry_run"`
+	IgnoreMissingSource types.Bool                      `tfsdk:"ignore_missing_source"`
+	ContinueOnError     types.Bool                      `tfsdk:"continue_on_error"`
+	Failures            types.List                      `tfsdk:"failures"`
+	WaitForSource       *CopyResourceWaitForSourceModel `tfsdk:"wait_for_source"`
+	Auth                *AuthOverrideModel              `tfsdk:"auth"`
+	SourceAuth          *AuthOverrideModel              `tfsdk:"source_auth"`
+	DestinationAuth     *AuthOverrideModel              `tfsdk:"destination_auth"`
+	Platform            types.String                    `tfsdk:"platform"`
+	Source              types.String                    `tfsdk:"source"`
+	Destination         types.String                    `tfsdk:"destination"`
+	Digest              types.String                    `tfsdk:"digest"`
+	DigestsMatch        types.Bool                      `tfsdk:"digests_match"`
+	BlobsMounted        types.Int64                     `tfsdk:"blobs_mounted"`
+	Id                  types.String                    `tfsdk:"id"`
+}
+
+// CopyResourceFailureModel describes a repository or image a recursive copy
+// failed for.
+type CopyResourceFailureModel struct {
+	Reference types.String `tfsdk:"reference"`
+	Error     types.String `tfsdk:"error"`
+}
+
+func (o CopyResourceFailureModel) AttributeTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"reference": types.StringType,
+		"error":     types.StringType,
+	}
+}
+
+// CopyResourceWaitForSourceModel describes how long to wait for the source
+// of a copy to appear.
+type CopyResourceWaitForSourceModel struct {
+	Timeout  types.String `tfsdk:"timeout"`
+	Interval types.String `tfsdk:"interval"`
+}
+
+// CopyResourceIdentityModel describes the resource identity data model.
+type CopyResourceIdentityModel struct {
 	Destination types.String `tfsdk:"destination"`
-	Id          types.String `tfsdk:"id"`
 }
 
 func (r *CopyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -59,10 +116,11 @@ func (r *CopyResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Copies container images between repositories",
 		Description:         "Copies container images between repositories",
+		Version:             copySchemaVersion(),
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Identifier",
+				MarkdownDescription: "Identifier: the destination and the digest of the copied image (`destination@digest`), or only the destination when `digest` is not set",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -74,6 +132,55 @@ func (r *CopyResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 					boolplanmodifier.RequiresReplace(),
 				},
 			},
+			"jobs": schema.Int64Attribute{
+				MarkdownDescription: "Number of concurrent copies for recursive copies (defaults to the provider `default_jobs`)",
+				Optional:            true,
+			},
+			"dry_run": schema.BoolAttribute{
+				MarkdownDescription: "Resolve the source and the destination and report what would be copied as a warning, without copying anything. Turning `dry_run` off performs the copy",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplaceIf(dryRunDisabled, "Performs the copy when dry_run is turned off", "Performs the copy when `dry_run` is turned off"),
+				},
+			},
+			"ignore_missing_source": schema.BoolAttribute{
+				MarkdownDescription: "Warn instead of failing when the source tag (or repository, for recursive copies) does not exist. Nothing is copied and `digest` is not set",
+				Optional:            true,
+			},
+			"continue_on_error": schema.BoolAttribute{
+				MarkdownDescription: "For recursive copies, carry on past the repositories and images that fail to copy, reporting them in `failures` and as a warning instead of failing the copy",
+				Optional:            true,
+			},
+			"failures": schema.ListNestedAttribute{
+				MarkdownDescription: "Repositories and images that failed to copy, when `continue_on_error` is set",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"reference": schema.StringAttribute{
+							MarkdownDescription: "Repository, tag or digest that failed to copy",
+							Computed:            true,
+						},
+						"error": schema.StringAttribute{
+							MarkdownDescription: "Error from the copy",
+							Computed:            true,
+						},
+					},
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"max_bandwidth_mbps": schema.Float64Attribute{
+				MarkdownDescription: "Maximum bandwidth for this copy in megabits per second, for uploads and downloads each, overriding the provider `max_bandwidth_mbps`",
+				Optional:            true,
+			},
+			"platform": schema.StringAttribute{
+				MarkdownDescription: "Copy only the image for this platform (for example `linux/amd64`) when the source is an image index (defaults to the provider `default_platform`, or the whole index). Not supported for recursive copies",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
 			"source": schema.StringAttribute{
 				MarkdownDescription: "Source for copy",
 				Required:            true,
@@ -84,9 +191,70 @@ func (r *CopyResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 			"destination": schema.StringAttribute{
 				MarkdownDescription: "Destination for copy",
 				Required:            true,
-				//PlanModifiers: []planmodifier.String{
-				//		stringplanmodifier.RequiresReplace(),
-				//	},
+				// The identity of a copy is its destination, which cannot
+				// change in place.
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"digest": schema.StringAttribute{
+				MarkdownDescription: "Digest of the copied image, verified to be the same in the source and the destination after the copy. Not set for recursive copies",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"blobs_mounted": schema.Int64Attribute{
+				MarkdownDescription: "Number of blobs mounted from the source repository instead of being pulled and pushed, which registries allow when the source and the destination are in the same registry",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"digests_match": schema.BoolAttribute{
+				MarkdownDescription: "Whether the source and the destination resolved to the same digest after the copy (the copy fails if they do not). Not set for recursive copies",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"auth": schema.SingleNestedBlock{
+				MarkdownDescription: "Credentials for the registries of the source and the destination of this copy, overriding the provider credentials, for example when copies push to registries owned by different teams. Set one of `docker_config`, `username` and `password`, or `access_token`",
+				Attributes:          authOverrideAttributes(),
+			},
+			"source_auth": schema.SingleNestedBlock{
+				MarkdownDescription: "Credentials for the registry of the source of this copy, overriding `auth` and the provider credentials, for example to copy from a password protected registry to Artifact Registry. Set one of `docker_config`, `username` and `password`, or `access_token`",
+				Attributes:          authOverrideAttributes(),
+			},
+			"destination_auth": schema.SingleNestedBlock{
+				MarkdownDescription: "Credentials for the registry of the destination of this copy, overriding `auth` and the provider credentials. Set one of `docker_config`, `username` and `password`, or `access_token`",
+				Attributes:          authOverrideAttributes(),
+			},
+			"wait_for_source": schema.SingleNestedBlock{
+				MarkdownDescription: "Wait for the source tag (or repository, for recursive copies) to appear before copying, for example while CI is still pushing it. Durations are given like `30s` or `2m`",
+				Attributes: map[string]schema.Attribute{
+					"timeout": schema.StringAttribute{
+						MarkdownDescription: "How long to wait for the source (defaults to `5m`). The copy fails if the source has not appeared by then, unless `ignore_missing_source` is set",
+						Optional:            true,
+					},
+					"interval": schema.StringAttribute{
+						MarkdownDescription: "How often to check for the source (defaults to `10s`)",
+						Optional:            true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *CopyResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"destination": identityschema.StringAttribute{
+				Description:       "Destination of the copy",
+				RequiredForImport: true,
 			},
 		},
 	}
@@ -111,6 +279,36 @@ func (r *CopyResource) Configure(ctx context.Context, req resource.ConfigureRequ
 	r.Client = client
 }
 
+func (r *CopyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is written when destroying, or before the provider is configured.
+	if req.Plan.Raw.IsNull() || r.Client == nil {
+		return
+	}
+
+	var destination types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("destination"), &destination)...)
+	if resp.Diagnostics.HasError() || destination.IsUnknown() || destination.IsNull() {
+		return
+	}
+
+	// Existing copies are only checked when their destination changes.
+	if !req.State.Raw.IsNull() {
+		var current types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("destination"), &current)...)
+		if resp.Diagnostics.HasError() || current.Equal(destination) {
+			return
+		}
+	}
+
+	if err := r.Client.Destinations.Check(destination.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("destination"),
+			"Destination not allowed",
+			err.Error(),
+		)
+	}
+}
+
 func (r *CopyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data CopyResourceModel
 
@@ -121,52 +319,231 @@ func (r *CopyResource) Create(ctx context.Context, req resource.CreateRequest, r
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 
+	// Credentials are write-only, so they are only in the configuration.
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("auth"), &data.Auth)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("source_auth"), &data.SourceAuth)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("destination_auth"), &data.DestinationAuth)...)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	resp.Diagnostics.Append(r.copy(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Id = types.StringValue(copyId(data.Destination.ValueString(), data.Digest.ValueString()))
+
+	tflog.Trace(ctx, "Performed a copy using gcrane", map[string]interface{}{
+		"recursive":   data.Recursive,
+		"source":      data.Source,
+		"destination": data.Destination,
+	})
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+}
+
+// copy performs the copy described by data, recording it in the audit log.
+func (r *CopyResource) copy(ctx context.Context, data *CopyResourceModel) (diags diag.Diagnostics) {
+	if !data.DryRun.ValueBool() {
+		diags.Append(r.Client.CheckWritable(fmt.Sprintf("copy %s to %s", data.Source.ValueString(), data.Destination.ValueString()))...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	if !data.Jobs.IsNull() && data.Jobs.ValueInt64() < 1 {
+		diags.AddAttributeError(path.Root("jobs"), "Invalid jobs", "Jobs must be one or greater.")
+		return diags
+	}
+
+	if !data.MaxBandwidthMbps.IsNull() {
+		if data.MaxBandwidthMbps.ValueFloat64() <= 0 {
+			diags.AddAttributeError(path.Root("max_bandwidth_mbps"), "Invalid max_bandwidth_mbps", "max_bandwidth_mbps must be greater than zero.")
+			return diags
+		}
+		ctx = withBandwidthLimit(ctx, newBandwidthLimit(data.MaxBandwidthMbps.ValueFloat64()))
+	}
+
+	data.Digest = types.StringNull()
+	data.DigestsMatch = types.BoolNull()
+	data.BlobsMounted = types.Int64Null()
+	data.Failures = types.ListNull(types.ObjectType{AttrTypes: CopyResourceFailureModel{}.AttributeTypes()})
+	if data.ContinueOnError.ValueBool() && !data.Recursive.ValueBool() {
+		diags.AddAttributeError(path.Root("continue_on_error"), "Invalid continue_on_error", "continue_on_error is only supported for recursive copies.")
+		return diags
+	}
+
+	platform := r.Client.DefaultPlatform
+	if !data.Platform.IsNull() {
+		if data.Recursive.ValueBool() {
+			diags.AddAttributeError(path.Root("platform"), "Invalid platform", "Platform is not supported for recursive copies.")
+			return diags
+		}
+		var err error
+		platform, err = v1.ParsePlatform(data.Platform.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("platform"), "Invalid platform", fmt.Sprintf("Unable to parse platform %q: %s", data.Platform.ValueString(), err))
+			return diags
+		}
+	}
+
+	keychain, d := r.keychainFor(data)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	if keychain != nil {
+		r = &CopyResource{Client: r.Client, keychain: keychain}
+	}
+
+	ctx, span := r.Client.Tracer.Start(ctx, "copy",
+		attribute.String("gcrane.source", data.Source.ValueString()),
+		attribute.String("gcrane.destination", data.Destination.ValueString()),
+		attribute.Bool("gcrane.recursive", data.Recursive.ValueBool()),
+		attribute.Bool("gcrane.dry_run", data.DryRun.ValueBool()),
+	)
+	defer func() { span.End(ctx, diags) }()
+
 	var err error
-	err = r.Client.Setup(ctx, *r.Client)
+	err = r.Client.Setup(ctx, r.Client)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Could not setup provider",
 			err.Error(),
 		)
-		return
+		return diags
 	}
 	defer func() {
-		err := r.Client.Cleanup(ctx, *r.Client)
+		err := r.Client.Cleanup(ctx, r.Client)
 		if err != nil {
-			resp.Diagnostics.AddError(
+			diags.AddError(
 				"Could not clean up provider",
 				err.Error(),
 			)
 		}
 	}()
 
-	data.Id = data.Destination
+	if data.WaitForSource != nil {
+		diags.Append(r.waitForSource(ctx, data, platform)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
 
-	if data.Recursive.ValueBool() {
-		err = gcrane.CopyRepository(ctx, data.Source.ValueString(), data.Destination.ValueString(), gcrane.WithContext(ctx))
+	if data.IgnoreMissingSource.ValueBool() {
+		exists, err := r.sourceExists(ctx, data, platform)
+		if err != nil {
+			diags.AddError(
+				"Could not resolve source",
+				fmt.Sprintf("Error when resolving %s: %s", data.Source.ValueString(), registryErrorDetail(err, r.Client.Failures)),
+			)
+			return diags
+		}
+		if !exists {
+			diags.AddAttributeWarning(
+				path.Root("source"),
+				"Source not found",
+				fmt.Sprintf("%s does not exist, nothing was copied to %s.", data.Source.ValueString(), data.Destination.ValueString()),
+			)
+			return diags
+		}
+	}
+
+	if data.DryRun.ValueBool() {
+		diags.Append(r.dryRun(ctx, data, platform)...)
+		return diags
+	}
+
+	mounts := &blobMounts{}
+	ctx = withBlobMounts(ctx, mounts)
+	ctx, progress := r.Client.Progress(ctx, "copy")
+
+	var failures []copyFailure
+	if data.ContinueOnError.ValueBool() {
+		failures, err = copyRepository(ctx, data.Source.ValueString(), data.Destination.ValueString(), r.Client.Jobs(data.Jobs), r.Keychain(), r.Client.Transport)
+		if err == nil {
+			diags.Append(r.reportFailures(ctx, data, failures)...)
+		}
+	} else if data.Recursive.ValueBool() {
+		err = gcrane.CopyRepository(ctx, data.Source.ValueString(), data.Destination.ValueString(), gcrane.WithContext(ctx), gcrane.WithKeychain(r.Keychain()), gcrane.WithTransport(r.Client.Transport), gcrane.WithJobs(r.Client.Jobs(data.Jobs)))
 	} else {
-		err = gcrane.Copy(data.Source.ValueString(), data.Destination.ValueString(), gcrane.WithContext(ctx))
+		progress.AddTotal(1)
+		// Pull through a configured mirror first, falling back to the
+		// source registry if the mirror does not have the image.
+		mirror, hasMirror := r.Client.Mirror(data.Source.ValueString())
+		if hasMirror {
+			err = r.copyImage(ctx, mirror, data.Destination.ValueString(), platform, data.Jobs)
+			if err != nil {
+				tflog.Warn(ctx, "Copy from registry mirror failed, copying from source registry", map[string]interface{}{
+					"mirror": mirror,
+					"error":  err.Error(),
+				})
+			}
+		}
+		if !hasMirror || err != nil {
+			err = r.copyImage(ctx, data.Source.ValueString(), data.Destination.ValueString(), platform, data.Jobs)
+		}
+		progress.Complete(data.Destination.ValueString())
+	}
+	progress.Finish()
+
+	data.BlobsMounted = types.Int64Value(mounts.count.Load())
+
+	// Re-resolve the source and the destination, as registries may convert
+	// manifests on the way.
+	var sourceDigest, destinationDigest string
+	if err == nil && !data.Recursive.ValueBool() {
+		sourceDigest, destinationDigest, err = r.copiedDigests(ctx, data.Source.ValueString(), data.Destination.ValueString(), platform)
+		if err == nil {
+			data.Digest = types.StringValue(destinationDigest)
+			data.DigestsMatch = types.BoolValue(sourceDigest == destinationDigest)
+			if sourceDigest != destinationDigest {
+				err = fmt.Errorf("digest of %s (%s) does not match the source %s (%s)", data.Destination.ValueString(), destinationDigest, data.Source.ValueString(), sourceDigest)
+			}
+		}
+	}
+
+	if r.Client.AuditLog != nil {
+		entry := auditEntry{
+			Operation:   "copy",
+			Source:      data.Source.ValueString(),
+			Destination: data.Destination.ValueString(),
+		}
+		if data.Recursive.ValueBool() {
+			entry.Operation = "copy_repository"
+		} else if sourceDigest != "" {
+			entry.SourceDigest = sourceDigest
+		} else {
+			entry.SourceDigest = r.sourceDigest(ctx, data.Source.ValueString(), platform)
+		}
+		auditResult := err
+		if auditResult == nil && len(failures) > 0 {
+			auditResult = fmt.Errorf("%d repositories or images failed to copy", len(failures))
+		}
+		if auditErr := r.Client.AuditLog.Record(entry, auditResult); auditErr != nil {
+			diags.AddWarning("Could not write audit log", auditErr.Error())
+		}
+	}
+
+	if !data.DigestsMatch.IsNull() && !data.DigestsMatch.ValueBool() {
+		diags.AddError(
+			"Copied image does not match source",
+			fmt.Sprintf("The copy was written, but %s.", err),
+		)
+		return diags
 	}
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Could not perform gcrane copy",
-			fmt.Sprintf("Error when copying using gcrane: %s", err.Error()),
+			fmt.Sprintf("Error when copying using gcrane: %s", registryErrorDetail(err, r.Client.Failures)),
 		)
-		return
+		return diags
 	}
 
-	tflog.Trace(ctx, "Performed a copy using gcrane", map[string]interface{}{
-		"recursive":   data.Recursive,
-		"source":      data.Source,
-		"destination": data.Destination,
-	})
-
-	// Save data into Terraform state
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return diags
 }
 
 func (r *CopyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -179,7 +556,74 @@ func (r *CopyResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	// Copies whose destination was deleted outside Terraform are removed
+	// from the state, so that they are copied again. Only copies of a single
+	// image have a digest: nothing is checked for recursive copies, dry runs
+	// and missing sources, except for copies made before digests were
+	// recorded, which get the digest of their destination. The credentials
+	// of auth blocks are not in the state, so copies with credentials for
+	// the destination are not checked either.
+	unrecorded := data.Digest.ValueString() == "" && !data.Recursive.ValueBool() && !data.DryRun.ValueBool() && !data.IgnoreMissingSource.ValueBool()
+	if (data.Digest.ValueString() != "" || unrecorded) && data.Auth == nil && data.DestinationAuth == nil {
+		digest, diags := r.destinationDigest(ctx, &data)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if digest == "" {
+			tflog.Warn(ctx, "Destination of copy no longer exists, removing it from state", map[string]interface{}{
+				"destination": data.Destination.ValueString(),
+			})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		if unrecorded {
+			data.Digest = types.StringValue(digest)
+			data.Id = types.StringValue(copyId(data.Destination.ValueString(), digest))
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
+}
+
+// destinationDigest returns the digest of the destination of a copy, or
+// an empty digest when it no longer exists, using the provider credentials.
+func (r *CopyResource) destinationDigest(ctx context.Context, data *CopyResourceModel) (digest string, diags diag.Diagnostics) {
+	ctx, span := r.Client.Tracer.Start(ctx, "read", attribute.String("gcrane.destination", data.Destination.ValueString()))
+	defer func() { span.End(ctx, diags) }()
+
+	err := r.Client.Setup(ctx, r.Client)
+	if err != nil {
+		diags.AddError(
+			"Could not setup provider",
+			err.Error(),
+		)
+		return "", diags
+	}
+	defer func() {
+		err := r.Client.Cleanup(ctx, r.Client)
+		if err != nil {
+			diags.AddError(
+				"Could not clean up provider",
+				err.Error(),
+			)
+		}
+	}()
+
+	digest, err = r.resolveDigest(ctx, data.Destination.ValueString(), nil)
+	switch {
+	case err == nil:
+		return digest, diags
+	case isManifestUnknown(err):
+		return "", diags
+	default:
+		diags.AddError(
+			"Could not read destination",
+			fmt.Sprintf("Error when resolving %s: %s", data.Destination.ValueString(), registryErrorDetail(err, r.Client.Failures)),
+		)
+		return "", diags
+	}
 }
 
 func (r *CopyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -192,7 +636,13 @@ func (r *CopyResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	resp.Diagnostics.Append(r.Client.CheckWritable(fmt.Sprintf("update the copy to %s", data.Destination.ValueString()))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Identity.Set(ctx, data.identity())...)
 }
 
 func (r *CopyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -207,5 +657,447 @@ func (r *CopyResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 }
 
 func (r *CopyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if req.ID == "" {
+		resource.ImportStatePassthroughWithIdentity(ctx, path.Root("id"), path.Root("destination"), req, resp)
+		resource.ImportStatePassthroughWithIdentity(ctx, path.Root("destination"), path.Root("destination"), req, resp)
+		return
+	}
+
+	destination, digest := parseCopyId(req.ID)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("destination"), destination)...)
+	if digest != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("digest"), digest)...)
+	}
+}
+
+// identity returns the resource identity of a copy. Copies imported by ID
+// before identities existed may only have an ID.
+func (m CopyResourceModel) identity() CopyResourceIdentityModel {
+	if m.Destination.IsNull() || m.Destination.IsUnknown() {
+		destination, _ := parseCopyId(m.Id.ValueString())
+		return CopyResourceIdentityModel{Destination: types.StringValue(destination)}
+	}
+	return CopyResourceIdentityModel{Destination: m.Destination}
+}
+
+// copyId returns the ID of a copy to destination: the destination and the
+// digest of the copied image, which stays the same when the destination
+// tag is moved later on, or only the destination when there is no digest,
+// like for recursive copies.
+func copyId(destination, digest string) string {
+	if digest == "" {
+		return destination
+	}
+	return destination + "@" + digest
+}
+
+// parseCopyId returns the destination and the digest of the ID of a copy.
+// IDs without a digest, like those of recursive copies and of copies made
+// by earlier versions, are the destination.
+func parseCopyId(id string) (string, string) {
+	i := strings.LastIndex(id, "@")
+	if i < 0 {
+		return id, ""
+	}
+	if _, err := v1.NewHash(id[i+1:]); err != nil {
+		return id, ""
+	}
+	return id[:i], id[i+1:]
+}
+
+// reportFailures records the failures of a recursive copy in data, and warns
+// about them.
+func (r *CopyResource) reportFailures(ctx context.Context, data *CopyResourceModel, failures []copyFailure) (diags diag.Diagnostics) {
+	models := make([]CopyResourceFailureModel, 0, len(failures))
+	var summary strings.Builder
+	for _, failure := range failures {
+		models = append(models, CopyResourceFailureModel{
+			Reference: types.StringValue(failure.Reference),
+			Error:     types.StringValue(registryErrorDetail(failure.Err, r.Client.Failures)),
+		})
+		fmt.Fprintf(&summary, "\n- %s: %s", failure.Reference, failure.Err)
+	}
+	var d diag.Diagnostics
+	data.Failures, d = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: CopyResourceFailureModel{}.AttributeTypes()}, models)
+	diags.Append(d...)
+
+	if len(failures) > 0 {
+		diags.AddWarning(
+			"Some images were not copied",
+			fmt.Sprintf("%d repositories or images failed to copy from %s to %s:%s", len(failures), data.Source.ValueString(), data.Destination.ValueString(), summary.String()),
+		)
+	}
+	return diags
+}
+
+// dryRun resolves what a copy would do and reports it as a warning.
+func (r *CopyResource) dryRun(ctx context.Context, data *CopyResourceModel, platform *v1.Platform) (diags diag.Diagnostics) {
+	source, destination := data.Source.ValueString(), data.Destination.ValueString()
+
+	var plan string
+	if data.Recursive.ValueBool() {
+		repo, err := name.NewRepository(source)
+		if err != nil {
+			diags.AddAttributeError(path.Root("source"), "Invalid source", err.Error())
+			return diags
+		}
+		tags, err := remote.List(repo, remote.WithContext(ctx), remote.WithAuthFromKeychain(r.Keychain()), remote.WithTransport(r.Client.Transport))
+		if err != nil {
+			diags.AddError(
+				"Could not list source repository",
+				fmt.Sprintf("Error when listing %s: %s", source, registryErrorDetail(err, r.Client.Failures)),
+			)
+			return diags
+		}
+		plan = fmt.Sprintf("Would copy %s (%d tags, and any child repositories) to %s.", source, len(tags), destination)
+	} else {
+		sourceDigest, err := r.resolveDigest(ctx, source, platform)
+		if err != nil {
+			diags.AddError(
+				"Could not resolve source",
+				fmt.Sprintf("Error when resolving %s: %s", source, registryErrorDetail(err, r.Client.Failures)),
+			)
+			return diags
+		}
+		destinationDigest, err := r.resolveDigest(ctx, destination, nil)
+		switch {
+		case err != nil && !isNotFound(err):
+			diags.AddError(
+				"Could not resolve destination",
+				fmt.Sprintf("Error when resolving %s: %s", destination, registryErrorDetail(err, r.Client.Failures)),
+			)
+			return diags
+		case err != nil:
+			plan = fmt.Sprintf("Would copy %s (%s) to %s.", source, sourceDigest, destination)
+		case destinationDigest == sourceDigest:
+			plan = fmt.Sprintf("%s is already a copy of %s (%s).", destination, source, sourceDigest)
+		default:
+			plan = fmt.Sprintf("Would copy %s (%s) to %s, replacing %s.", source, sourceDigest, destination, destinationDigest)
+		}
+	}
+
+	tflog.Info(ctx, "Dry run of copy", map[string]interface{}{
+		"source":      source,
+		"destination": destination,
+		"plan":        plan,
+	})
+	diags.AddWarning("Dry run: nothing was copied", plan)
+	return diags
+}
+
+// sourceExists reports whether the source of a copy exists: the image, or
+// the repository for recursive copies.
+func (r *CopyResource) sourceExists(ctx context.Context, data *CopyResourceModel, platform *v1.Platform) (bool, error) {
+	var err error
+	if data.Recursive.ValueBool() {
+		var repo name.Repository
+		repo, err = name.NewRepository(data.Source.ValueString())
+		if err != nil {
+			return false, err
+		}
+		_, err = remote.List(repo, remote.WithContext(ctx), remote.WithAuthFromKeychain(r.Keychain()), remote.WithTransport(r.Client.Transport))
+	} else {
+		_, err = r.resolveDigest(ctx, data.Source.ValueString(), platform)
+	}
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// waitForSource polls until the source of a copy exists, failing when it
+// does not appear within the timeout unless missing sources are ignored.
+func (r *CopyResource) waitForSource(ctx context.Context, data *CopyResourceModel, platform *v1.Platform) (diags diag.Diagnostics) {
+	timeout, interval := 5*time.Minute, 10*time.Second
+	parseDurations(path.Root("wait_for_source"), []durationAttribute{
+		{name: "timeout", value: data.WaitForSource.Timeout, out: &timeout},
+		{name: "interval", value: data.WaitForSource.Interval, out: &interval},
+	}, &diags)
+	if interval <= 0 {
+		diags.AddAttributeError(path.Root("wait_for_source").AtName("interval"), "Invalid interval", "interval must be greater than zero.")
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		exists, err := r.sourceExists(ctx, data, platform)
+		if err != nil {
+			diags.AddError(
+				"Could not resolve source",
+				fmt.Sprintf("Error when resolving %s: %s", data.Source.ValueString(), registryErrorDetail(err, r.Client.Failures)),
+			)
+			return diags
+		}
+		if exists {
+			return diags
+		}
+		if time.Now().Add(interval).After(deadline) {
+			break
+		}
+
+		tflog.Info(ctx, "Waiting for source to appear", map[string]interface{}{
+			"source":   data.Source.ValueString(),
+			"interval": interval.String(),
+		})
+		select {
+		case <-ctx.Done():
+			diags.AddError("Stopped waiting for source", ctx.Err().Error())
+			return diags
+		case <-time.After(interval):
+		}
+	}
+
+	if !data.IgnoreMissingSource.ValueBool() {
+		diags.AddAttributeError(
+			path.Root("wait_for_source"),
+			"Source not found",
+			fmt.Sprintf("%s did not appear within %s.", data.Source.ValueString(), timeout),
+		)
+	}
+	return diags
+}
+
+// keychainFor returns the keychain for the auth blocks of a copy, or nil
+// when none are set. The credentials of auth apply to the registries of the
+// source and the destination, those of source_auth and destination_auth to
+// the registry of the source and the destination respectively.
+func (r *CopyResource) keychainFor(data *CopyResourceModel) (keychain authn.Keychain, diags diag.Diagnostics) {
+	if data.Auth == nil && data.SourceAuth == nil && data.DestinationAuth == nil {
+		return nil, diags
+	}
+
+	source, err := copyRepositoryOf(data.Source.ValueString(), data.Recursive.ValueBool())
+	if err != nil {
+		diags.AddAttributeError(path.Root("source"), "Invalid source", err.Error())
+	}
+	destination, err := copyRepositoryOf(data.Destination.ValueString(), data.Recursive.ValueBool())
+	if err != nil {
+		diags.AddAttributeError(path.Root("destination"), "Invalid destination", err.Error())
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	keychain = r.Client.Keychain
+	if data.Auth != nil {
+		keychain, err = data.Auth.keychain(r.Client.Keychain, source.Registry, destination.Registry)
+		if err != nil {
+			diags.AddAttributeError(path.Root("auth"), "Invalid auth", err.Error())
+			return nil, diags
+		}
+	}
+	if data.SourceAuth == nil && data.DestinationAuth == nil {
+		return keychain, diags
+	}
+
+	sourceKeychain, destinationKeychain := keychain, keychain
+	if data.SourceAuth != nil {
+		sourceKeychain, err = data.SourceAuth.keychain(keychain, source.Registry)
+		if err != nil {
+			diags.AddAttributeError(path.Root("source_auth"), "Invalid source_auth", err.Error())
+		}
+	}
+	if data.DestinationAuth != nil {
+		destinationKeychain, err = data.DestinationAuth.keychain(keychain, destination.Registry)
+		if err != nil {
+			diags.AddAttributeError(path.Root("destination_auth"), "Invalid destination_auth", err.Error())
+		}
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+	return copyKeychain{
+		source:              source,
+		destination:         destination,
+		sourceKeychain:      sourceKeychain,
+		destinationKeychain: destinationKeychain,
+	}, diags
+}
+
+// copyRepositoryOf returns the repository of the source or the destination
+// of a copy, which is the reference itself for recursive copies.
+func copyRepositoryOf(reference string, recursive bool) (name.Repository, error) {
+	if recursive {
+		return name.NewRepository(reference)
+	}
+	ref, err := name.ParseReference(reference)
+	if err != nil {
+		return name.Repository{}, err
+	}
+	return ref.Context(), nil
+}
+
+// copyKeychain resolves credentials for the source and the destination of a
+// copy (and their child repositories) from separate keychains.
+// Registry-wide requests, like the listings of recursive copies, use the
+// destination keychain for the destination registry and the source keychain
+// for all others, including when both are in the same registry.
+type copyKeychain struct {
+	source, destination                 name.Repository
+	sourceKeychain, destinationKeychain authn.Keychain
+}
+
+var _ authn.ContextKeychain = copyKeychain{}
+
+func (k copyKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	return k.ResolveContext(context.Background(), target)
+}
+
+func (k copyKeychain) ResolveContext(ctx context.Context, target authn.Resource) (authn.Authenticator, error) {
+	within := func(repo name.Repository) bool {
+		return target.String() == repo.String() || strings.HasPrefix(target.String(), repo.String()+"/")
+	}
+	switch {
+	case within(k.source):
+		return authn.Resolve(ctx, k.sourceKeychain, target)
+	case within(k.destination):
+		return authn.Resolve(ctx, k.destinationKeychain, target)
+	case target.RegistryStr() == k.destination.RegistryStr() && target.RegistryStr() != k.source.RegistryStr():
+		return authn.Resolve(ctx, k.destinationKeychain, target)
+	default:
+		return authn.Resolve(ctx, k.sourceKeychain, target)
+	}
+}
+
+// Keychain returns the keychain of the copy: the credentials of its auth
+// blocks, or the provider credentials.
+func (r *CopyResource) Keychain() authn.Keychain {
+	if r.keychain != nil {
+		return r.keychain
+	}
+	return r.Client.Keychain
+}
+
+// dryRunDisabled requires replacing a copy when dry_run is turned off, so
+// the copy is performed.
+func dryRunDisabled(ctx context.Context, req planmodifier.BoolRequest, resp *boolplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = req.StateValue.ValueBool() && !req.PlanValue.ValueBool()
+}
+
+// copyImage copies a single image, or only the image for platform if it is
+// set and the source is an image index.
+func (r *CopyResource) copyImage(ctx context.Context, source, destination string, platform *v1.Platform, jobs types.Int64) error {
+	cache := r.layerCacheFor(source, destination)
+	opts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(r.Keychain()), remote.WithTransport(r.Client.Transport), remote.WithJobs(r.Client.Jobs(jobs))}
+	if platform != nil {
+		copied, err := copyPlatform(source, destination, *platform, cache, opts...)
+		if err != nil || copied {
+			return err
+		}
+	}
+	if cache != nil {
+		return copyCached(source, destination, cache, opts...)
+	}
+	return gcrane.Copy(source, destination, gcrane.WithContext(ctx), gcrane.WithKeychain(r.Keychain()), gcrane.WithTransport(r.Client.Transport), gcrane.WithJobs(r.Client.Jobs(jobs)))
+}
+
+// layerCacheFor returns the provider layer cache for a copy from source to
+// destination, or nil if there is none. Copies within a registry do not use
+// the cache, as their layers are mounted instead of downloaded.
+func (r *CopyResource) layerCacheFor(source, destination string) *layerCache {
+	if r.Client.LayerCache == nil {
+		return nil
+	}
+	src, err := name.ParseReference(source)
+	if err != nil {
+		return nil
+	}
+	dst, err := name.ParseReference(destination)
+	if err != nil || src.Context().RegistryStr() == dst.Context().RegistryStr() {
+		return nil
+	}
+	return r.Client.LayerCache
+}
+
+// copyPlatform copies the image for platform from src to dst when src is an
+// image index, reading its layers through cache if set. It returns false
+// without copying anything for other sources.
+func copyPlatform(src, dst string, platform v1.Platform, cache *layerCache, opts ...remote.Option) (bool, error) {
+	srcRef, err := name.ParseReference(src)
+	if err != nil {
+		return false, fmt.Errorf("parsing reference %q: %w", src, err)
+	}
+	dstRef, err := name.ParseReference(dst)
+	if err != nil {
+		return false, fmt.Errorf("parsing reference for %q: %w", dst, err)
+	}
+	opts = append(opts, remote.WithPlatform(platform))
+	desc, err := remote.Get(srcRef, opts...)
+	if err != nil {
+		return false, fmt.Errorf("fetching %s: %w", src, err)
+	}
+	if !desc.MediaType.IsIndex() {
+		return false, nil
+	}
+	img, err := desc.Image()
+	if err != nil {
+		return false, fmt.Errorf("resolving %s for platform %s: %w", src, platform.String(), err)
+	}
+	if cache != nil {
+		img = cache.Image(img)
+	}
+	if err := remote.Write(dstRef, img, opts...); err != nil {
+		return false, fmt.Errorf("writing %s: %w", dst, err)
+	}
+	return true, nil
+}
+
+// sourceDigest returns the digest of the copied source image for the audit
+// log, or an empty string if it can not be resolved.
+func (r *CopyResource) sourceDigest(ctx context.Context, source string, platform *v1.Platform) string {
+	digest, err := r.resolveDigest(ctx, source, platform)
+	if err != nil {
+		return ""
+	}
+	return digest
+}
+
+// copiedDigests resolves the digests of the source and the destination of a
+// copy.
+func (r *CopyResource) copiedDigests(ctx context.Context, source, destination string, platform *v1.Platform) (string, string, error) {
+	sourceDigest, err := r.resolveDigest(ctx, source, platform)
+	if err != nil {
+		return "", "", fmt.Errorf("verifying %s: %w", source, err)
+	}
+	destinationDigest, err := r.resolveDigest(ctx, destination, nil)
+	if err != nil {
+		return "", "", fmt.Errorf("verifying %s: %w", destination, err)
+	}
+	return sourceDigest, destinationDigest, nil
+}
+
+// resolveDigest returns the digest of reference, or of the image for
+// platform if it is set and reference is an image index.
+func (r *CopyResource) resolveDigest(ctx context.Context, reference string, platform *v1.Platform) (string, error) {
+	ref, err := name.ParseReference(reference)
+	if err != nil {
+		return "", err
+	}
+	opts := []remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(r.Keychain()), remote.WithTransport(r.Client.Transport)}
+	if platform != nil {
+		desc, err := remote.Get(ref, append(opts, remote.WithPlatform(*platform))...)
+		if err != nil {
+			return "", err
+		}
+		if !desc.MediaType.IsIndex() {
+			return desc.Digest.String(), nil
+		}
+		img, err := desc.Image()
+		if err != nil {
+			return "", err
+		}
+		digest, err := img.Digest()
+		if err != nil {
+			return "", err
+		}
+		return digest.String(), nil
+	}
+	desc, err := remote.Head(ref, opts...)
+	if err != nil {
+		return "", err
+	}
+	return desc.Digest.String(), nil
 }
@@ -17,45 +17,735 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"strings"
+	"regexp"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestAccExampleResource(t *testing.T) {
-	source := os.Getenv("GCRANE_SOURCE")
-	if source != "" {
-		a := strings.Split(source, ":")
-		randBytes := make([]byte, 16)
-		_, err := rand.Read(randBytes)
-		if err != nil {
-			panic(err)
-		}
-		target := a[0] + ":" + hex.EncodeToString(randBytes)
-
-		resource.Test(t, resource.TestCase{
-			PreCheck:                 func() { testAccPreCheck(t) },
-			ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-			Steps: []resource.TestStep{
-				// Create and Read testing
-				{
-					Config: testAccExampleResourceConfig(source, target),
-					ConfigStateChecks: []statecheck.StateCheck{
-						statecheck.ExpectKnownValue(
-							"gcrane_copy.copied_image",
-							tfjsonpath.New("id"),
-							knownvalue.StringExact(target),
-						),
+	registry := newTestRegistry(t)
+	digest := registry.SeedRandomImage(t, "source/image:latest")
+
+	randBytes := make([]byte, 16)
+	_, err := rand.Read(randBytes)
+	if err != nil {
+		panic(err)
+	}
+	source := registry.Ref("source/image:latest")
+	target := registry.Ref("target/image:" + hex.EncodeToString(randBytes))
+	moved := registry.Ref("target/moved:" + hex.EncodeToString(randBytes))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: testAccExampleResourceConfig(source, target),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact(target+"@"+digest),
+					),
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digest),
+					),
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("digests_match"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+			// Import by ID testing
+			{
+				ResourceName:  "gcrane_copy.copied_image",
+				ImportState:   true,
+				ImportStateId: target + "@" + digest,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected one imported copy, got %d", len(states))
+					}
+					if got := states[0].Attributes["destination"]; got != target {
+						return fmt.Errorf("expected destination %s, got %s", target, got)
+					}
+					if got := states[0].Attributes["digest"]; got != digest {
+						return fmt.Errorf("expected digest %s, got %s", digest, got)
+					}
+					return nil
+				},
+			},
+			// Changing the destination replaces the copy
+			{
+				Config: testAccExampleResourceConfig(source, moved),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("gcrane_copy.copied_image", plancheck.ResourceActionReplace),
 					},
 				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact(moved+"@"+digest),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestParseCopyId(t *testing.T) {
+	digest := "sha256:4a1c4b21597c1b4415bdbecb28a3296c6b5e23ca4f9feeb599860a1dac6a0108"
+	tests := []struct {
+		id          string
+		destination string
+		digest      string
+	}{
+		{"example.com/repo/image:latest@" + digest, "example.com/repo/image:latest", digest},
+		{"example.com/repo/image:latest", "example.com/repo/image:latest", ""},
+		{"example.com/repo/image@" + digest + "@" + digest, "example.com/repo/image@" + digest, digest},
+		{"example.com/repo/image@notadigest", "example.com/repo/image@notadigest", ""},
+	}
+	for _, tt := range tests {
+		destination, digest := parseCopyId(tt.id)
+		if destination != tt.destination || digest != tt.digest {
+			t.Errorf("parseCopyId(%q) = %q, %q, want %q, %q", tt.id, destination, digest, tt.destination, tt.digest)
+		}
+		if tt.digest != "" && copyId(destination, digest) != tt.id {
+			t.Errorf("copyId(%q, %q) = %q, want %q", destination, digest, copyId(destination, digest), tt.id)
+		}
+	}
+}
+
+func TestCopyKeychainFor(t *testing.T) {
+	provider := authn.AuthConfig{Username: "provider", Password: "provider-secret"}
+	r := &CopyResource{Client: &GcraneData{Keychain: staticKeychain{
+		"harbor.example.com":    provider,
+		"registry.example.com":  provider,
+		"europe-docker.pkg.dev": provider,
+		"ghcr.io":               provider,
+	}}}
+	harbor := authn.AuthConfig{Username: "harbor", Password: "source-secret"}
+	token := authn.AuthConfig{Username: "_token", Password: "ya29.token"}
+
+	tests := []struct {
+		name string
+		data CopyResourceModel
+		want map[string]authn.AuthConfig
+	}{
+		{
+			name: "auth",
+			data: CopyResourceModel{
+				Source:      types.StringValue("harbor.example.com/project/app:v1"),
+				Destination: types.StringValue("registry.example.com/mirror/app:v1"),
+				Auth:        &AuthOverrideModel{Username: types.StringValue("harbor"), Password: types.StringValue("source-secret")},
+			},
+			want: map[string]authn.AuthConfig{
+				"harbor.example.com/project/app":  harbor,
+				"registry.example.com/mirror/app": harbor,
+				"ghcr.io/owner/base":              provider,
+			},
+		},
+		{
+			name: "source and destination auth",
+			data: CopyResourceModel{
+				Source:          types.StringValue("harbor.example.com/project/app:v1"),
+				Destination:     types.StringValue("europe-docker.pkg.dev/project/repo/app:v1"),
+				SourceAuth:      &AuthOverrideModel{Username: types.StringValue("harbor"), Password: types.StringValue("source-secret")},
+				DestinationAuth: &AuthOverrideModel{AccessToken: types.StringValue("ya29.token")},
+			},
+			want: map[string]authn.AuthConfig{
+				"harbor.example.com/project/app":            harbor,
+				"harbor.example.com/project/other":          harbor,
+				"europe-docker.pkg.dev/project/repo/app":    token,
+				"europe-docker.pkg.dev/project/other/image": token,
+				// Other registries, like those of base images, use the
+				// provider credentials.
+				"ghcr.io/owner/base": provider,
 			},
+		},
+		{
+			name: "source auth only",
+			data: CopyResourceModel{
+				Source:      types.StringValue("harbor.example.com/project/app:v1"),
+				Destination: types.StringValue("europe-docker.pkg.dev/project/repo/app:v1"),
+				SourceAuth:  &AuthOverrideModel{Username: types.StringValue("harbor"), Password: types.StringValue("source-secret")},
+			},
+			want: map[string]authn.AuthConfig{
+				"harbor.example.com/project/app":         harbor,
+				"europe-docker.pkg.dev/project/repo/app": provider,
+				"ghcr.io/owner/base":                     provider,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keychain, diags := r.keychainFor(&tt.data)
+			if diags.HasError() {
+				t.Fatalf("keychainFor() failed: %v", diags)
+			}
+			for repository, want := range tt.want {
+				if got := resolveAuth(t, keychain, repository); got != want {
+					t.Errorf("credentials for %s = %+v, want %+v", repository, got, want)
+				}
+			}
 		})
 	}
+
+	// Access tokens are only for Google registries.
+	_, diags := r.keychainFor(&CopyResourceModel{
+		Source:          types.StringValue("europe-docker.pkg.dev/project/repo/app:v1"),
+		Destination:     types.StringValue("harbor.example.com/project/app:v1"),
+		DestinationAuth: &AuthOverrideModel{AccessToken: types.StringValue("ya29.token")},
+	})
+	if !diags.HasError() || diags.Errors()[0].Summary() != "Invalid destination_auth" {
+		t.Errorf("keychainFor() with an access token for harbor.example.com = %v, want an invalid destination_auth", diags)
+	}
+}
+
+func TestAccCopyResourceDestinationDeleted(t *testing.T) {
+	registry := newTestRegistry(t)
+	digest := registry.SeedRandomImage(t, "source/image:latest")
+	source, target := registry.Ref("source/image:latest"), registry.Ref("target/deleted:latest")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExampleResourceConfig(source, target),
+			},
+			// The destination is deleted outside Terraform, so the copy is
+			// removed from the state and planned again.
+			{
+				PreConfig: func() {
+					registry.Delete(t, "target/deleted:latest")
+				},
+				Config:             testAccExampleResourceConfig(source, target),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccExampleResourceConfig(source, target),
+				Check: func(*terraform.State) error {
+					copied, err := registry.Digest("target/deleted:latest")
+					if err != nil {
+						return err
+					}
+					if copied != digest {
+						return fmt.Errorf("expected %s to be copied again, got %s", digest, copied)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccCopyResourcePlatform(t *testing.T) {
+	registry := newTestRegistry(t)
+	_, digests := registry.PushIndex(t, "source/multiarch:latest",
+		testImage{Platform: "linux/amd64", Labels: map[string]string{"arch": "amd64"}},
+		testImage{Platform: "linux/arm64", Layers: 2, Labels: map[string]string{"arch": "arm64"}},
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCopyResourcePlatformConfig(registry.Ref("source/multiarch:latest"), registry.Ref("target/arm64:latest"), "linux/arm64"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digests["linux/arm64"]),
+					),
+				},
+				Check: func(*terraform.State) error {
+					digest, err := registry.Digest("target/arm64:latest")
+					if err != nil {
+						return err
+					}
+					if digest != digests["linux/arm64"] {
+						return fmt.Errorf("expected the linux/arm64 image %s to be copied, got %s", digests["linux/arm64"], digest)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccCopyResourceDryRun(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.SeedRandomImage(t, "source/image:latest")
+	source := registry.Ref("source/image:latest")
+	target := registry.Ref("target/dry-run:latest")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCopyResourceDryRunConfig(source, target, true),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("digest"),
+						knownvalue.Null(),
+					),
+				},
+				Check: func(*terraform.State) error {
+					if _, err := registry.Digest("target/dry-run:latest"); err == nil {
+						return fmt.Errorf("expected %s not to be copied in a dry run", target)
+					}
+					return nil
+				},
+			},
+			// Turning dry_run off performs the copy
+			{
+				Config: testAccCopyResourceDryRunConfig(source, target, false),
+				Check: func(*terraform.State) error {
+					_, err := registry.Digest("target/dry-run:latest")
+					return err
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyResourceDryRunConfig(source string, target string, dryRun bool) string {
+	return fmt.Sprintf(`
+resource "gcrane_copy" "copied_image" {
+  source      = "%s"
+  destination = "%s"
+  dry_run     = %t
+}
+`, source, target, dryRun)
+}
+
+func TestAccCopyResourceIgnoreMissingSource(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCopyResourceIgnoreMissingSourceConfig(registry.Ref("source/missing:latest"), registry.Ref("target/missing:latest")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("digest"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyResourceIgnoreMissingSourceConfig(source string, target string) string {
+	return fmt.Sprintf(`
+resource "gcrane_copy" "copied_image" {
+  source                = "%s"
+  destination           = "%s"
+  ignore_missing_source = true
+}
+`, source, target)
+}
+
+func TestAccCopyResourceWaitForSource(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Push the source while the copy is waiting for it
+				PreConfig: func() {
+					go func() {
+						time.Sleep(2 * time.Second)
+						registry.SeedRandomImage(t, "source/pending:latest")
+					}()
+				},
+				Config: testAccCopyResourceWaitForSourceConfig(registry.Ref("source/pending:latest"), registry.Ref("target/pending:latest")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("digests_match"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyResourceWaitForSourceConfig(source string, target string) string {
+	return fmt.Sprintf(`
+resource "gcrane_copy" "copied_image" {
+  source      = "%s"
+  destination = "%s"
+
+  wait_for_source {
+    timeout  = "1m"
+    interval = "1s"
+  }
+}
+`, source, target)
+}
+
+func TestAccCopyResourceContinueOnError(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.SeedRandomImage(t, "source/repo:one")
+	registry.SeedRandomImage(t, "source/repo:two")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCopyResourceContinueOnErrorConfig(registry.Ref("source/repo"), registry.Ref("target/repo")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_repository",
+						tfjsonpath.New("failures"),
+						knownvalue.ListExact([]knownvalue.Check{}),
+					),
+				},
+				Check: func(*terraform.State) error {
+					for _, tag := range []string{"one", "two"} {
+						if _, err := registry.Digest("target/repo:" + tag); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyResourceContinueOnErrorConfig(source string, target string) string {
+	return fmt.Sprintf(`
+resource "gcrane_copy" "copied_repository" {
+  source            = "%s"
+  destination       = "%s"
+  recursive         = true
+  continue_on_error = true
+}
+`, source, target)
+}
+
+func TestAccCopyResourceMaxBandwidth(t *testing.T) {
+	registry := newTestRegistry(t)
+	digest := registry.PushImage(t, "source/image:latest", testImage{Layers: 4})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCopyResourceMaxBandwidthConfig(registry.Ref("source/image:latest"), registry.Ref("target/throttled:latest")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digest),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyResourceMaxBandwidthConfig(source string, target string) string {
+	return fmt.Sprintf(`
+provider "gcrane" {
+  max_bandwidth_mbps = 1
+}
+
+resource "gcrane_copy" "copied_image" {
+  source             = "%s"
+  destination        = "%s"
+  max_bandwidth_mbps = 0.5
+}
+`, source, target)
+}
+
+func TestAccCopyResourceCacheDir(t *testing.T) {
+	source := newTestRegistry(t)
+	target := newTestRegistry(t)
+	digest := source.PushImage(t, "source/image:latest", testImage{Layers: 3})
+	cacheDir := t.TempDir()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCopyResourceCacheDirConfig(cacheDir, source.Ref("source/image:latest"), target.Ref("target/one:latest"), target.Ref("target/two:latest")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.one",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digest),
+					),
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.two",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digest),
+					),
+				},
+				Check: func(*terraform.State) error {
+					entries, err := os.ReadDir(cacheDir)
+					if err != nil {
+						return err
+					}
+					if len(entries) != 3 {
+						return fmt.Errorf("expected 3 cached layers, got %d", len(entries))
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyResourceCacheDirConfig(cacheDir string, source string, one string, two string) string {
+	return fmt.Sprintf(`
+provider "gcrane" {
+  cache_dir = "%s"
+}
+
+resource "gcrane_copy" "one" {
+  source      = "%s"
+  destination = "%s"
+}
+
+resource "gcrane_copy" "two" {
+  source      = "%s"
+  destination = "%s"
+}
+`, cacheDir, source, one, source, two)
+}
+
+func TestAccCopyResourceAuth(t *testing.T) {
+	registry := newTestRegistryWithAuth(t, "team", "s3cret")
+	digest := registry.SeedRandomImage(t, "source/image:latest")
+	source, target := registry.Ref("source/image:latest"), registry.Ref("target/authenticated:latest")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The credentials are write-only.
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCopyResourceAuthConfig(source, target, "wrong"),
+				ExpectError: regexp.MustCompile(`did not accept the credentials`),
+			},
+			{
+				Config: testAccCopyResourceAuthConfig(source, target, "s3cret"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digest),
+					),
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("auth").AtMapKey("password"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyResourceAuthConfig(source string, target string, password string) string {
+	return fmt.Sprintf(`
+resource "gcrane_copy" "copied_image" {
+  source      = "%s"
+  destination = "%s"
+
+  auth {
+    username = "team"
+    password = "%s"
+  }
+}
+`, source, target, password)
+}
+
+func TestAccCopyResourceSourceDestinationAuth(t *testing.T) {
+	source := newTestRegistryWithAuth(t, "harbor", "source-secret")
+	destination := newTestRegistryWithAuth(t, "artifact-registry", "destination-secret")
+	digest := source.SeedRandomImage(t, "project/image:latest")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		// The credentials are write-only.
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCopyResourceSourceDestinationAuthConfig(source.Ref("project/image:latest"), destination.Ref("mirror/image:latest")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"gcrane_copy.copied_image",
+						tfjsonpath.New("digest"),
+						knownvalue.StringExact(digest),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyResourceSourceDestinationAuthConfig(source string, target string) string {
+	return fmt.Sprintf(`
+resource "gcrane_copy" "copied_image" {
+  source      = "%s"
+  destination = "%s"
+
+  source_auth {
+    username = "harbor"
+    password = "source-secret"
+  }
+
+  destination_auth {
+    username = "artifact-registry"
+    password = "destination-secret"
+  }
+}
+`, source, target)
+}
+
+// newTestCollector starts an OTLP/HTTP trace collector, returning its URL
+// and a function returning the spans exported so far, by name, with their
+// string attributes.
+func newTestCollector(t *testing.T) (string, func() map[string]map[string]string) {
+	t.Helper()
+
+	var lock sync.Mutex
+	spans := map[string]map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var export coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &export); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		for _, resourceSpans := range export.ResourceSpans {
+			for _, scopeSpans := range resourceSpans.ScopeSpans {
+				for _, span := range scopeSpans.Spans {
+					attrs := map[string]string{}
+					for _, attr := range span.Attributes {
+						attrs[attr.Key] = attr.Value.GetStringValue()
+					}
+					spans[span.Name] = attrs
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, func() map[string]map[string]string {
+		lock.Lock()
+		defer lock.Unlock()
+		return maps.Clone(spans)
+	}
+}
+
+func TestAccCopyResourceTracing(t *testing.T) {
+	registry := newTestRegistry(t)
+	registry.SeedRandomImage(t, "source/image:latest")
+	endpoint, spans := newTestCollector(t)
+	source, target := registry.Ref("source/image:latest"), registry.Ref("target/traced:latest")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCopyResourceTracingConfig(endpoint, source, target),
+				Check: func(*terraform.State) error {
+					exported := spans()
+					copySpan, ok := exported["gcrane.copy"]
+					if !ok {
+						return fmt.Errorf("no gcrane.copy span exported, got %v", exported)
+					}
+					if copySpan["gcrane.source"] != source || copySpan["gcrane.destination"] != target {
+						return fmt.Errorf("unexpected gcrane.copy span attributes %v", copySpan)
+					}
+					if push := exported["registry push"]; push["gcrane.reference"] != target {
+						return fmt.Errorf("unexpected registry push span attributes %v", push)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccCopyResourceTracingConfig(endpoint string, source string, target string) string {
+	return fmt.Sprintf(`
+provider "gcrane" {
+  tracing_endpoint = "%s"
+}
+
+resource "gcrane_copy" "copied_image" {
+  source      = "%s"
+  destination = "%s"
+}
+`, endpoint, source, target)
+}
+
+func testAccCopyResourcePlatformConfig(source string, target string, platform string) string {
+	return fmt.Sprintf(`
+resource "gcrane_copy" "copied_image" {
+  source      = "%s"
+  destination = "%s"
+  platform    = "%s"
+}
+`, source, target, platform)
 }
 
 func testAccExampleResourceConfig(source string, target string) string {
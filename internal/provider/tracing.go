@@ -0,0 +1,304 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingFlushTimeout bounds how long an operation waits for its spans to
+// be exported, so an unreachable collector does not stall applies.
+const tracingFlushTimeout = 5 * time.Second
+
+// tracer exports OpenTelemetry spans of registry operations over OTLP/HTTP.
+type tracer struct {
+	provider *sdktrace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// newTracer returns a tracer exporting to endpoint, or to the endpoint set
+// in the standard OTEL_EXPORTER_OTLP_* environment variables. Tracing is
+// disabled (a nil tracer) when no endpoint is configured.
+func newTracer(ctx context.Context, endpoint string, version string) (*tracer, error) {
+	var opts []otlptracehttp.Option
+	switch {
+	case endpoint != "":
+		opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+	case os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "":
+		return nil, nil
+	}
+	opts = append(opts,
+		otlptracehttp.WithTimeout(tracingFlushTimeout),
+		otlptracehttp.WithRetry(otlptracehttp.RetryConfig{Enabled: false}),
+	)
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			attribute.String("service.name", "terraform-provider-gcrane"),
+			attribute.String("service.version", version),
+		),
+		// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence.
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	return &tracer{
+		provider: provider,
+		tracer:   provider.Tracer("github.com/rosmo/terraform-provider-gcrane"),
+	}, nil
+}
+
+// operationSpanKey is the context key of the operationSpan of a request.
+type operationSpanKey struct{}
+
+// operationSpan is the span of a provider operation, like a copy, adding up
+// the bytes transferred by the registry requests made for it.
+type operationSpan struct {
+	tracer   *tracer
+	span     trace.Span
+	sent     atomic.Int64
+	received atomic.Int64
+	requests atomic.Int64
+}
+
+// Start starts a span for operation (like "copy") with attrs, to be ended
+// with End. Starting a span on a nil tracer returns a nil span, which does
+// nothing.
+func (t *tracer) Start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, *operationSpan) {
+	if t == nil {
+		return ctx, nil
+	}
+	ctx, span := t.tracer.Start(ctx, "gcrane."+operation, trace.WithAttributes(attrs...))
+	op := &operationSpan{tracer: t, span: span}
+	return context.WithValue(ctx, operationSpanKey{}, op), op
+}
+
+// End ends the span, marking it failed if diags has errors, and exports it.
+func (s *operationSpan) End(ctx context.Context, diags diag.Diagnostics) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(
+		attribute.Int64("gcrane.bytes_sent", s.sent.Load()),
+		attribute.Int64("gcrane.bytes_received", s.received.Load()),
+		attribute.Int64("gcrane.requests", s.requests.Load()),
+	)
+	if errs := diags.Errors(); len(errs) > 0 {
+		s.span.SetStatus(codes.Error, errs[0].Summary())
+		for _, e := range errs {
+			s.span.RecordError(fmt.Errorf("%s: %s", e.Summary(), e.Detail()))
+		}
+	}
+	s.span.End()
+
+	// The provider process can be stopped at any time after an operation,
+	// so spans are exported as operations end rather than in the background.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tracingFlushTimeout)
+	defer cancel()
+	if err := s.tracer.provider.ForceFlush(ctx); err != nil {
+		tflog.Warn(ctx, "Could not export traces", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// tracingTransport records a span per registry request, with the
+// operation (like a manifest HEAD) and the image reference it is for.
+type tracingTransport struct {
+	inner  http.RoundTripper
+	tracer *tracer
+}
+
+func (t tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation, reference := registryRequest(req)
+	ctx, span := t.tracer.tracer.Start(req.Context(), "registry "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("gcrane.operation", operation),
+		),
+	)
+	if reference != "" {
+		span.SetAttributes(attribute.String("gcrane.reference", reference))
+	}
+	op, _ := ctx.Value(operationSpanKey{}).(*operationSpan)
+	if op != nil {
+		op.requests.Add(1)
+	}
+
+	body := &tracedBody{span: span, op: op}
+	req = req.Clone(ctx)
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = &countingBody{ReadCloser: req.Body, count: &body.sent}
+	}
+
+	resp, err := t.inner.RoundTrip(req)
+	if err != nil {
+		body.end(err)
+		return resp, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	body.ReadCloser = resp.Body
+	resp.Body = body
+	return resp, nil
+}
+
+// countingBody counts the bytes read from a request body.
+type countingBody struct {
+	io.ReadCloser
+	count *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.count.Add(int64(n))
+	return n, err
+}
+
+// tracedBody counts the bytes read from a response body, and ends the span
+// of the request when the body is closed.
+type tracedBody struct {
+	io.ReadCloser
+	span     trace.Span
+	op       *operationSpan
+	sent     atomic.Int64
+	received atomic.Int64
+	once     sync.Once
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.received.Add(int64(n))
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.end(nil)
+	return err
+}
+
+// end ends the span of the request, failed with err if it is not nil.
+func (b *tracedBody) end(err error) {
+	b.once.Do(func() {
+		sent, received := b.sent.Load(), b.received.Load()
+		b.span.SetAttributes(
+			attribute.Int64("http.request.body.size", sent),
+			attribute.Int64("http.response.body.size", received),
+		)
+		if err != nil {
+			b.span.RecordError(err)
+			b.span.SetStatus(codes.Error, err.Error())
+		}
+		b.span.End()
+		if b.op != nil {
+			b.op.sent.Add(sent)
+			b.op.received.Add(received)
+		}
+	})
+}
+
+// registryRequest classifies a registry API request as an operation (like
+// "head" or "list") and returns the image reference or repository it is
+// for, if any.
+func registryRequest(req *http.Request) (string, string) {
+	p := req.URL.Path
+	if !strings.HasPrefix(p, "/v2/") {
+		// Token exchanges and redirected blob downloads.
+		if req.Method == http.MethodGet && req.URL.Query().Get("scope") != "" {
+			return "auth", ""
+		}
+		return strings.ToLower(req.Method), ""
+	}
+	p = strings.TrimPrefix(p, "/v2/")
+	if p == "" {
+		return "ping", ""
+	}
+	if p == "_catalog" {
+		return "list", req.URL.Host
+	}
+
+	var repo, kind, ref string
+	for _, k := range []string{"/manifests/", "/blobs/uploads/", "/blobs/", "/tags/list"} {
+		if i := strings.LastIndex(p, k); i > 0 {
+			repo, kind, ref = p[:i], strings.Trim(k, "/"), p[i+len(k):]
+			break
+		}
+	}
+	if repo == "" {
+		return strings.ToLower(req.Method), ""
+	}
+	repository := req.URL.Host + "/" + repo
+
+	switch {
+	case kind == "tags/list":
+		return "list", repository
+	case req.Method == http.MethodHead:
+		return "head", referenceIn(repository, ref)
+	case req.Method == http.MethodDelete:
+		return "delete", referenceIn(repository, ref)
+	case kind == "blobs/uploads":
+		if req.URL.Query().Get("mount") != "" {
+			return "mount", repository + "@" + req.URL.Query().Get("mount")
+		}
+		return "push", repository
+	case req.Method == http.MethodGet:
+		return "pull", referenceIn(repository, ref)
+	default:
+		return "push", referenceIn(repository, ref)
+	}
+}
+
+// referenceIn returns the reference of a tag or digest in repository.
+func referenceIn(repository, ref string) string {
+	if ref == "" {
+		return repository
+	}
+	if strings.Contains(ref, ":") {
+		return repository + "@" + ref
+	}
+	return repository + ":" + ref
+}
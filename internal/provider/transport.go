@@ -0,0 +1,434 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/time/rate"
+)
+
+// transportConfig holds the provider settings for the HTTP transport used
+// for all registry calls.
+type transportConfig struct {
+	// CACertificates are PEM encoded certificates or paths to PEM files.
+	CACertificates []string
+	// InsecureSkipVerify lists registries whose TLS certificates are not
+	// verified.
+	InsecureSkipVerify []string
+	// PlainHTTP lists registries that are served over plain HTTP.
+	PlainHTTP []string
+	// Proxy overrides the proxy settings from the environment when any of
+	// its fields are set.
+	Proxy httpproxy.Config
+	// UserAgent is appended to the User-Agent of every request.
+	UserAgent string
+	// Headers are added to every request.
+	Headers map[string]string
+	// Debug logs every request and response.
+	Debug bool
+	// RequestsPerSecond throttles requests across all registries, 0 for
+	// no limit.
+	RequestsPerSecond float64
+	// MaxBandwidthMbps throttles uploads and downloads to this many
+	// megabits per second each, 0 for no limit. Contexts can override it
+	// with withBandwidthLimit.
+	MaxBandwidthMbps float64
+	// Timeouts overrides the connection timeouts of the transport.
+	Timeouts transportTimeouts
+	// Retry is the retry policy for failed requests, nil to not retry.
+	Retry *retryPolicy
+	// Failures records the failed responses (after retries) for
+	// diagnostics, if set.
+	Failures *failureLog
+	// Tracer records a span per request, if set.
+	Tracer *tracer
+}
+
+// transportTimeouts are connection settings, zero values keep the defaults.
+type transportTimeouts struct {
+	Dial                time.Duration
+	TLSHandshake        time.Duration
+	ResponseHeader      time.Duration
+	IdleConnection      time.Duration
+	MaxIdleConnsPerHost int
+}
+
+// retryPolicy describes when and how often failed requests are retried.
+type retryPolicy struct {
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	RetryOn     map[int]bool
+}
+
+// defaultRetryPolicy is used for settings missing from the retries block.
+var defaultRetryPolicy = retryPolicy{
+	MaxAttempts: 5,
+	MinBackoff:  time.Second,
+	MaxBackoff:  30 * time.Second,
+	RetryOn: map[int]bool{
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusGatewayTimeout:      true,
+	},
+}
+
+// newTransport builds the HTTP transport shared by all registry calls.
+func newTransport(cfg transportConfig) (http.RoundTripper, error) {
+	t, err := newBaseTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var limit *bandwidthLimit
+	if cfg.MaxBandwidthMbps > 0 {
+		limit = newBandwidthLimit(cfg.MaxBandwidthMbps)
+	}
+	t = bandwidthTransport{inner: t, limit: limit}
+	if cfg.Debug {
+		t = debugTransport{t}
+	}
+	if cfg.RequestsPerSecond > 0 {
+		burst := int(math.Ceil(cfg.RequestsPerSecond))
+		t = rateLimitTransport{
+			inner:   t,
+			limiter: rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), burst),
+		}
+	}
+	if cfg.Retry != nil && cfg.Retry.MaxAttempts > 1 {
+		t = retryTransport{inner: t, policy: *cfg.Retry}
+	}
+	if cfg.UserAgent != "" || len(cfg.Headers) > 0 {
+		t = headerTransport{
+			inner:     t,
+			userAgent: cfg.UserAgent,
+			headers:   cfg.Headers,
+		}
+	}
+	t = blobMountTransport{t}
+	if cfg.Failures != nil {
+		t = failureTransport{inner: t, log: cfg.Failures}
+	}
+	if cfg.Tracer != nil {
+		t = tracingTransport{inner: t, tracer: cfg.Tracer}
+	}
+	return t, nil
+}
+
+// newBaseTransport builds the TLS, proxy and per-registry routing layers.
+func newBaseTransport(cfg transportConfig) (http.RoundTripper, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+
+	if cfg.Timeouts.Dial > 0 {
+		t.DialContext = (&net.Dialer{
+			Timeout:   cfg.Timeouts.Dial,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+	if cfg.Timeouts.TLSHandshake > 0 {
+		t.TLSHandshakeTimeout = cfg.Timeouts.TLSHandshake
+	}
+	if cfg.Timeouts.ResponseHeader > 0 {
+		t.ResponseHeaderTimeout = cfg.Timeouts.ResponseHeader
+	}
+	if cfg.Timeouts.IdleConnection > 0 {
+		t.IdleConnTimeout = cfg.Timeouts.IdleConnection
+	}
+	if cfg.Timeouts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = cfg.Timeouts.MaxIdleConnsPerHost
+	}
+
+	if cfg.Proxy != (httpproxy.Config{}) {
+		proxy := cfg.Proxy.ProxyFunc()
+		t.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		}
+	}
+
+	if len(cfg.CACertificates) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, cert := range cfg.CACertificates {
+			pem := []byte(cert)
+			if !strings.Contains(cert, "-----BEGIN") {
+				pem, err = os.ReadFile(cert)
+				if err != nil {
+					return nil, fmt.Errorf("unable to read CA certificate file %s: %w", cert, err)
+				}
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no valid PEM certificates found in %s", truncate(cert, 64))
+			}
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+
+	if len(cfg.InsecureSkipVerify) == 0 && len(cfg.PlainHTTP) == 0 {
+		return t, nil
+	}
+
+	hosts := hostTransport{
+		hosts:    map[string]http.RoundTripper{},
+		fallback: t,
+	}
+	insecure := t.Clone()
+	insecure.TLSClientConfig.InsecureSkipVerify = true
+	for _, address := range cfg.InsecureSkipVerify {
+		host, err := registryKey(address)
+		if err != nil {
+			return nil, err
+		}
+		hosts.hosts[host] = insecure
+	}
+	for _, address := range cfg.PlainHTTP {
+		host, err := registryKey(address)
+		if err != nil {
+			return nil, err
+		}
+		hosts.hosts[host] = plainHTTPTransport{t}
+	}
+	return hosts, nil
+}
+
+// hostTransport routes requests to a per-registry transport, falling back
+// to the default transport for all other hosts.
+type hostTransport struct {
+	hosts    map[string]http.RoundTripper
+	fallback http.RoundTripper
+}
+
+func (t hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt, ok := t.hosts[req.URL.Host]; ok {
+		return rt.RoundTrip(req)
+	}
+	return t.fallback.RoundTrip(req)
+}
+
+// plainHTTPTransport sends requests over plain HTTP, for registries that
+// are not served over TLS. go-containerregistry only does this by itself
+// for localhost and references parsed with name.Insecure.
+type plainHTTPTransport struct {
+	inner http.RoundTripper
+}
+
+func (t plainHTTPTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "https" {
+		req = req.Clone(req.Context())
+		req.URL.Scheme = "http"
+	}
+	return t.inner.RoundTrip(req)
+}
+
+// headerTransport adds the configured User-Agent suffix and headers to
+// every request.
+type headerTransport struct {
+	inner     http.RoundTripper
+	userAgent string
+	headers   map[string]string
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	if t.userAgent != "" {
+		if ua := req.Header.Get("User-Agent"); ua != "" {
+			req.Header.Set("User-Agent", ua+" "+t.userAgent)
+		} else {
+			req.Header.Set("User-Agent", t.userAgent)
+		}
+	}
+	return t.inner.RoundTrip(req)
+}
+
+// debugTransport logs requests and responses through tflog, with
+// credentials redacted.
+type debugTransport struct {
+	inner http.RoundTripper
+}
+
+// redactedHeaders are never logged by debugTransport.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+func (t debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	u := *req.URL
+	u.User = nil
+	if u.RawQuery != "" {
+		// Query parameters can carry upload state or signed URL tokens.
+		u.RawQuery = "REDACTED"
+	}
+	fields := map[string]interface{}{
+		"method":          req.Method,
+		"url":             u.String(),
+		"request_headers": debugHeaders(req.Header),
+	}
+
+	start := time.Now()
+	resp, err := t.inner.RoundTrip(req)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "Registry request failed", fields)
+		return resp, err
+	}
+	fields["status"] = resp.StatusCode
+	fields["response_headers"] = debugHeaders(resp.Header)
+	tflog.Debug(ctx, "Registry request", fields)
+	return resp, nil
+}
+
+// debugHeaders flattens headers for logging, redacting credentials.
+func debugHeaders(h http.Header) map[string]string {
+	headers := make(map[string]string, len(h))
+	for k, v := range h {
+		if redactedHeaders[http.CanonicalHeaderKey(k)] {
+			headers[k] = "REDACTED"
+			continue
+		}
+		headers[k] = strings.Join(v, ", ")
+	}
+	return headers
+}
+
+// rateLimitTransport throttles requests, including retries, so that long
+// running copies stay under the pull quotas of registries like Docker Hub.
+type rateLimitTransport struct {
+	inner   http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.inner.RoundTrip(req)
+}
+
+// retryTransport retries requests failing with a network error or one of
+// the configured status codes, with exponential backoff. Requests with a
+// body that cannot be replayed (like streamed blob uploads) are not retried.
+type retryTransport struct {
+	inner  http.RoundTripper
+	policy retryPolicy
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	backoff := t.policy.MinBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := t.inner.RoundTrip(req)
+		if attempt >= t.policy.MaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+		if err == nil && !t.policy.RetryOn[resp.StatusCode] {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		wait := backoff
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"host":    req.URL.Host,
+			"attempt": attempt,
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			if after := retryAfter(resp); after > wait {
+				wait = after
+			}
+			// Drain the body so the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+		if wait > t.policy.MaxBackoff {
+			wait = t.policy.MaxBackoff
+		}
+		fields["wait_ms"] = wait.Milliseconds()
+		tflog.Debug(ctx, "Retrying registry request", fields)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > t.policy.MaxBackoff {
+			backoff = t.policy.MaxBackoff
+		}
+	}
+}
+
+// retryAfter returns the delay requested by a Retry-After header, if any.
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// truncate shortens s for use in error messages.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
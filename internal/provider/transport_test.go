@@ -0,0 +1,197 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testRetryPolicy retries the default status codes without waiting long.
+func testRetryPolicy() retryPolicy {
+	policy := defaultRetryPolicy
+	policy.MaxAttempts = 3
+	policy.MinBackoff = time.Millisecond
+	policy.MaxBackoff = 10 * time.Millisecond
+	return policy
+}
+
+// newStatusServer starts a server that answers with the given status codes
+// in turn, then with 200 OK, and counts the requests it received.
+func newStatusServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := int(requests.Add(1))
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestRetryTransportStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		want     int
+		requests int64
+	}{
+		{
+			name:     "retried until it succeeds",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests},
+			want:     http.StatusOK,
+			requests: 3,
+		},
+		{
+			name:     "not retried",
+			statuses: []int{http.StatusNotFound},
+			want:     http.StatusNotFound,
+			requests: 1,
+		},
+		{
+			name:     "attempts exhausted",
+			statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			want:     http.StatusBadGateway,
+			requests: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newStatusServer(t, nil, tt.statuses...)
+			client := &http.Client{Transport: retryTransport{inner: http.DefaultTransport, policy: testRetryPolicy()}}
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("Get() failed: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if got := requests.Load(); got != tt.requests {
+				t.Errorf("sent %d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	// The registry asks to wait an hour, longer than the maximum backoff.
+	server, requests := newStatusServer(t, http.Header{"Retry-After": {"3600"}}, http.StatusTooManyRequests)
+	client := &http.Client{Transport: retryTransport{inner: http.DefaultTransport, policy: testRetryPolicy()}}
+
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waited %s, want at most the maximum backoff", elapsed)
+	}
+	if resp.StatusCode != http.StatusOK || requests.Load() != 2 {
+		t.Errorf("status = %d after %d requests, want 200 after 2", resp.StatusCode, requests.Load())
+	}
+}
+
+func TestRetryTransportBody(t *testing.T) {
+	var (
+		lock   sync.Mutex
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		lock.Lock()
+		defer lock.Unlock()
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+	transport := retryTransport{inner: http.DefaultTransport, policy: testRetryPolicy()}
+
+	// Bodies that can be read again are sent again.
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("manifest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() failed: %v", err)
+	}
+	resp.Body.Close()
+	lock.Lock()
+	if resp.StatusCode != http.StatusOK || len(bodies) != 2 || bodies[1] != "manifest" {
+		t.Errorf("status = %d with bodies %q, want 200 after sending the body twice", resp.StatusCode, bodies)
+	}
+	// Streamed bodies are not retried.
+	bodies = nil
+	lock.Unlock()
+
+	req, err = http.NewRequest(http.MethodPatch, server.URL, io.NopCloser(strings.NewReader("layer")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() failed: %v", err)
+	}
+	resp.Body.Close()
+	lock.Lock()
+	defer lock.Unlock()
+	if resp.StatusCode != http.StatusServiceUnavailable || len(bodies) != 1 {
+		t.Errorf("status = %d after %d requests, want 503 after 1", resp.StatusCode, len(bodies))
+	}
+}
+
+func TestRetryTransportContext(t *testing.T) {
+	server, requests := newStatusServer(t, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	policy := testRetryPolicy()
+	policy.MinBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	transport := retryTransport{inner: http.DefaultTransport, policy: policy}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = transport.RoundTrip(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("waited %s after the context was done", elapsed)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+}